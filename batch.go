@@ -0,0 +1,276 @@
+package frontbase
+
+/*
+#include "clib.h"
+*/
+import "C"
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Oops-AB/go-frontbase/prepared"
+)
+
+//
+// Batch execution
+//
+
+// A BatchResult holds the outcome of each row of a batch, in the
+// order of the argument sets.
+type BatchResult struct {
+	RowsAffected []int64 // 0 for rows that failed
+	Errors       []error // nil for rows that succeeded
+}
+
+// Err returns the errors of the failed rows joined, or nil when all
+// rows succeeded.
+func (res BatchResult) Err() error {
+	return errors.Join(res.Errors...)
+}
+
+// ExecBatch executes `query` once for each argument set in `args`,
+// on conn. The query is parsed once and each argument set bound to
+// it. An argument may be an sql.NamedArg to bind a named
+// placeholder.
+//
+// The bound statements are sent to the server up to 100 at a
+// time, in one call. A row that fails, to bind or on the server, is
+// recorded in the result and the batch goes on with the next row;
+// run the batch in a transaction to make it all or nothing. The
+// batch stops when the context is done or the connection is lost,
+// and that error is returned along with the results of the rows
+// executed so far.
+func ExecBatch(ctx context.Context, conn *sql.Conn, query string, args [][]any) (BatchResult, error) {
+	var res BatchResult
+
+	err := conn.Raw(func(driverConn any) error {
+		dc, ok := driverConn.(*Conn)
+		if !ok {
+			return fmt.Errorf("ExecBatch: %T is not a FrontBase connection", driverConn)
+		}
+
+		var err error
+		res, err = dc.execBatch(ctx, query, args)
+		return err
+	})
+
+	return res, err
+}
+
+// The number of bound statements ExecBatch sends in one call.
+const batchSize = 100
+
+// The savepoint a batch executed in a transaction is undone to when
+// one of its statements fails.
+const batchSavepoint = "frontbase_batch"
+
+func (dc *Conn) execBatch(ctx context.Context, query string, args [][]any) (BatchResult, error) {
+	pstmt, err := dc.parse(query)
+	if err != nil {
+		return BatchResult{}, err
+	}
+
+	res := BatchResult{
+		RowsAffected: make([]int64, 0, len(args)),
+		Errors:       make([]error, 0, len(args)),
+	}
+
+	for first := 0; first < len(args); first += batchSize {
+		rows := args[first:min(first+batchSize, len(args))]
+
+		if err := dc.execBatchChunk(ctx, pstmt, rows, first, &res); err != nil {
+			return res, err
+		}
+	}
+
+	return res, nil
+}
+
+// Execute the rows of a batch starting with row `first` and append
+// their outcome to `res`. The bound statements are sent in one call;
+// when one of them fails their changes are undone and each is
+// executed on its own, to tell which rows failed.
+func (dc *Conn) execBatchChunk(ctx context.Context, pstmt *prepared.Stmt, rows [][]any, first int, res *BatchResult) error {
+	affected := make([]int64, len(rows))
+	errs := make([]error, len(rows))
+
+	var bound []string
+	var boundRows []int
+	var lobs lobHandles
+	defer func() { lobs.release() }()
+
+	for i, row := range rows {
+		sql, handles, err := dc.bindBatchRow(pstmt, row)
+		lobs = append(lobs, handles...)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		bound = append(bound, sql)
+		boundRows = append(boundRows, i)
+	}
+
+	// A single statement gains nothing from a script.
+	batched := len(bound) > 1
+
+	var counts []int64
+	var err error
+	if batched {
+		counts, err = dc.execBatchScript(ctx, bound)
+		if err != nil && (dc.bad || ctx.Err() != nil) {
+			return err
+		}
+	}
+
+	// Append the outcome of the first n rows to res.
+	appendResults := func(n int) {
+		for i := 0; i < n; i++ {
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("row %d: %w", first+i, errs[i])
+			}
+			res.RowsAffected = append(res.RowsAffected, affected[i])
+			res.Errors = append(res.Errors, errs[i])
+		}
+	}
+
+	for j, i := range boundRows {
+		if batched && err == nil {
+			affected[i] = counts[j]
+			continue
+		}
+
+		md, rowErr := dc.exec(ctx, bound[j], !dc.inTx, false)
+		if rowErr != nil {
+			errs[i] = rowErr
+		} else {
+			affected[i] = newResult(md).rowsAffected
+			C.fbcmdRelease(md)
+		}
+
+		// The row's outcome is known even when the batch stops here.
+		if dc.bad || ctx.Err() != nil {
+			appendResults(i + 1)
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return driver.ErrBadConn
+		}
+	}
+
+	appendResults(len(rows))
+	return nil
+}
+
+// Bind one row of a batch, writing any LOBs among its arguments
+// first. The LOBs are released by the caller.
+func (dc *Conn) bindBatchRow(pstmt *prepared.Stmt, row []any) (string, lobHandles, error) {
+	named, err := dc.appendNamedValues(nil, row)
+	if err != nil {
+		return "", nil, err
+	}
+
+	named, lobs, err := dc.writeLOBs(named)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql, err := pstmt.BindNamed(named)
+	return sql, lobs, err
+}
+
+// Execute the bound statements of a batch in one call, all or
+// nothing, and return the number of rows each affected. On an error
+// that leaves the connection usable nothing was changed.
+func (dc *Conn) execBatchScript(ctx context.Context, bound []string) ([]int64, error) {
+	if dc.inTx {
+		if err := dc.Savepoint(ctx, batchSavepoint); err != nil {
+			return nil, err
+		}
+	}
+
+	var counts []int64
+	md, err := dc.exec(ctx, strings.Join(bound, "\n"), false, false)
+	if err == nil {
+		counts = scriptRowsAffected(md)
+		C.fbcmdRelease(md)
+
+		if len(counts) != len(bound) {
+			err = fmt.Errorf("batch: %d results for %d statements", len(counts), len(bound))
+		}
+	}
+
+	if err == nil {
+		err = dc.endBatchScript(ctx, true)
+	}
+	if err != nil {
+		if dc.bad || ctx.Err() != nil {
+			return nil, err
+		}
+		if undoErr := dc.endBatchScript(context.Background(), false); undoErr != nil {
+			// What the batch changed is unknown.
+			dc.bad = true
+			return nil, undoErr
+		}
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// Keep or undo the changes of a batch script: release or roll back
+// to the savepoint in a transaction, commit or roll back otherwise.
+func (dc *Conn) endBatchScript(ctx context.Context, keep bool) error {
+	if dc.inTx {
+		if keep {
+			return dc.Release(ctx, batchSavepoint)
+		}
+		return dc.RollbackTo(ctx, batchSavepoint)
+	}
+
+	query := "rollback;"
+	if keep {
+		query = "commit;"
+	}
+
+	md, err := dc.exec(ctx, query, true, false)
+	if err != nil {
+		return err
+	}
+	C.fbcmdRelease(md)
+	return nil
+}
+
+// The number of rows affected by each statement of a script.
+func scriptRowsAffected(md *C.FBCMetaData) []int64 {
+	if C.fbcmdHasMetaDataArray(md) == 0 {
+		return []int64{newResult(md).rowsAffected}
+	}
+
+	counts := make([]int64, C.fbcmdCount(md))
+	for i := range counts {
+		counts[i] = newResult(C.fbcmdMetaDataAtIndex(md, C.uint(i))).rowsAffected
+	}
+	return counts
+}
+
+// Convert the arguments of `row` and append them to `named`,
+// numbering them on from the last one there.
+func (dc *Conn) appendNamedValues(named []driver.NamedValue, row []any) ([]driver.NamedValue, error) {
+	for _, arg := range row {
+		nv := driver.NamedValue{Ordinal: len(named) + 1, Value: arg}
+		if na, ok := arg.(sql.NamedArg); ok {
+			nv.Name, nv.Value = na.Name, na.Value
+		}
+
+		if err := dc.CheckNamedValue(&nv); err != nil {
+			return nil, err
+		}
+		named = append(named, nv)
+	}
+
+	return named, nil
+}
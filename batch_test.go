@@ -0,0 +1,104 @@
+package frontbase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestExecBatch(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( a int primary key, b character varying(20) );")
+
+	ctx := context.Background()
+	conn, err := tdb.db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	res, err := ExecBatch(ctx, conn, "insert into t0 values ( ?, @b );", [][]any{
+		{1, sql.Named("b", "one")},
+		{2, sql.Named("b", "two")},
+		{1, sql.Named("b", "duplicate")},
+		{3},
+		{4, sql.Named("b", "four")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []int64{1, 1, 0, 0, 1}
+	for i, n := range expected {
+		if res.RowsAffected[i] != n {
+			t.Errorf("row %d: expected %d rows affected, got %d", i, n, res.RowsAffected[i])
+		}
+		if failed := res.Errors[i] != nil; failed != (n == 0) {
+			t.Errorf("row %d: unexpected error %v", i, res.Errors[i])
+		}
+	}
+
+	if !IsUniqueViolation(res.Errors[2]) {
+		t.Errorf("expected a unique violation, got %v", res.Errors[2])
+	}
+
+	var count int32
+	if err := tdb.db.QueryRow("select count(*) from t0;").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 rows, got %d", count)
+	}
+}
+
+func TestExecBatch_in_transaction(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( a int primary key );")
+
+	ctx := context.Background()
+	conn, err := tdb.db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	// More rows than fit in one call, with a failing one among them.
+	args := make([][]any, 2*batchSize+1)
+	for i := range args {
+		args[i] = []any{i}
+	}
+	args[batchSize+3] = []any{7}
+
+	res, err := ExecBatch(ctx, conn, "insert into t0 values ( ? );", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range args {
+		if failed := res.Errors[i] != nil; failed != (i == batchSize+3) {
+			t.Errorf("row %d: unexpected error %v", i, res.Errors[i])
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	var count int32
+	if err := tdb.db.QueryRow("select count(*) from t0;").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2*batchSize {
+		t.Errorf("expected %d rows, got %d", 2*batchSize, count)
+	}
+}
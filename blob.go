@@ -0,0 +1,292 @@
+package frontbase
+
+/*
+#include "clib.h"
+*/
+import "C"
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"unsafe"
+
+	"github.com/Oops-AB/go-frontbase/prepared"
+)
+
+//
+// BLOB and CLOB values
+//
+
+// A Blob is a BLOB or CLOB value that is read, and written,
+// through an io.Reader. FBCAccess reads and writes a LOB in one
+// piece, so the whole value is held in C memory, once: a Blob is
+// not streamed from or to the server, it saves the copy into Go
+// memory.
+//
+// Scanned from a connection opened with the DSN option lobs=blob
+// the data is kept in the memory FBCAccess read it into, and Read
+// copies straight out of it. Created with NewBlob or NewClob for
+// binding, the reader is drained into the memory handed to
+// FBCAccess when the statement is executed. CLOB text must not
+// contain NUL bytes.
+//
+// Close releases the memory early; otherwise it is released when
+// the Blob is garbage collected.
+type Blob struct {
+	buf  *cBuffer  // data read from the server, or nil
+	off  int       // read offset into buf
+	src  io.Reader // data to read when buf is nil
+	clob bool
+}
+
+// NewBlob returns a Blob, to be bound as a BLOB, that reads its
+// data from r.
+func NewBlob(r io.Reader) *Blob {
+	return &Blob{src: r}
+}
+
+// NewClob returns a Blob, to be bound as a CLOB, that reads its
+// text from r.
+func NewClob(r io.Reader) *Blob {
+	return &Blob{src: r, clob: true}
+}
+
+// IsClob reports whether b holds character data.
+func (b *Blob) IsClob() bool {
+	return b.clob
+}
+
+// Len returns the number of unread bytes, or -1 when b reads from
+// a reader of unknown length.
+func (b *Blob) Len() int {
+	if b.buf == nil {
+		if b.src == nil {
+			return 0
+		}
+		return -1
+	}
+	return b.buf.size - b.off
+}
+
+func (b *Blob) Read(p []byte) (int, error) {
+	if b.buf == nil {
+		if b.src == nil {
+			return 0, io.EOF
+		}
+		return b.src.Read(p)
+	}
+
+	if b.off >= b.buf.size {
+		return 0, io.EOF
+	}
+
+	n := copy(p, b.buf.bytes()[b.off:])
+	b.off += n
+	return n, nil
+}
+
+func (b *Blob) Close() error {
+	if b.buf != nil {
+		b.buf.free()
+		b.buf = nil
+	}
+	b.src = nil
+	return nil
+}
+
+// sql.Scanner
+func (b *Blob) Scan(src any) error {
+	switch v := src.(type) {
+	case *Blob:
+		*b = Blob{buf: v.buf, clob: v.clob}
+	case []byte:
+		*b = Blob{src: bytes.NewReader(bytes.Clone(v))}
+	case string:
+		*b = Blob{src: strings.NewReader(v), clob: true}
+	case nil:
+		return fmt.Errorf("can't scan NULL into Blob, use sql.Null[Blob]")
+	default:
+		return fmt.Errorf("can't scan %T into Blob", src)
+	}
+	return nil
+}
+
+//
+// Memory allocated by C
+//
+
+// A cBuffer owns memory allocated with malloc, e.g. by FBCAccess.
+type cBuffer struct {
+	ptr  unsafe.Pointer
+	size int
+}
+
+func newCBuffer(ptr unsafe.Pointer, size int) *cBuffer {
+	buf := &cBuffer{ptr: ptr, size: size}
+	runtime.SetFinalizer(buf, (*cBuffer).free)
+	return buf
+}
+
+func (buf *cBuffer) bytes() []byte {
+	if buf.ptr == nil {
+		return nil
+	}
+	return unsafe.Slice((*byte)(buf.ptr), buf.size)
+}
+
+func (buf *cBuffer) free() {
+	C.free(buf.ptr)
+	buf.ptr = nil
+	buf.size = 0
+	runtime.SetFinalizer(buf, nil)
+}
+
+var errOutOfMemory = errors.New("out of memory for LOB data")
+
+// Read all of r into C memory, followed by a NUL byte that is not
+// counted in the size. The caller frees the memory.
+func readAllC(r io.Reader) (unsafe.Pointer, int, error) {
+	capacity := 64 * 1024
+	ptr := C.malloc(C.size_t(capacity))
+	if ptr == nil {
+		return nil, 0, errOutOfMemory
+	}
+	size := 0
+
+	for {
+		if size == capacity-1 {
+			capacity *= 2
+			grown := C.realloc(ptr, C.size_t(capacity))
+			if grown == nil {
+				C.free(ptr)
+				return nil, 0, errOutOfMemory
+			}
+			ptr = grown
+		}
+
+		chunk := unsafe.Slice((*byte)(ptr), capacity)[size : capacity-1]
+		n, err := r.Read(chunk)
+		size += n
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			C.free(ptr)
+			return nil, 0, err
+		}
+	}
+
+	*(*byte)(unsafe.Add(ptr, size)) = 0
+	return ptr, size, nil
+}
+
+//
+// Reading and writing through the connection
+//
+
+// Decode the BLOB or CLOB in `col`, as a *Blob when so configured
+// and otherwise as []byte or string.
+func (dc *Conn) readLOB(col *C.FBCColumn, clob bool) (driver.Value, error) {
+	var ptr unsafe.Pointer
+	var size int
+
+	if clob {
+		cstr := C.GoFBReadCLOB(dc.conn, col)
+		if cstr == nil {
+			return nil, fmt.Errorf("reading CLOB failed")
+		}
+		ptr, size = unsafe.Pointer(cstr), int(C.strlen(cstr))
+	} else {
+		var csize C.uint
+		if C.GoFBReadBLOB(dc.conn, col, &ptr, &csize) == 0 {
+			return nil, fmt.Errorf("reading %d bytes of BLOB failed", int(csize))
+		}
+		size = int(csize)
+	}
+
+	if dc.blobLOBs {
+		return &Blob{buf: newCBuffer(ptr, size), clob: clob}, nil
+	}
+
+	defer C.free(ptr)
+	if clob {
+		return C.GoStringN((*C.char)(ptr), C.int(size)), nil
+	}
+	return C.GoBytes(ptr, C.int(size)), nil
+}
+
+// The handles of LOBs written for one execution; released once
+// the statement has executed.
+type lobHandles []*C.FBCBlobHandle
+
+func (handles lobHandles) release() {
+	for _, handle := range handles {
+		C.fbcbhRelease(handle)
+	}
+}
+
+// Write the data of `b` to the server and return a literal that
+// refers to it.
+func (dc *Conn) writeLOB(b *Blob) (prepared.Literal, *C.FBCBlobHandle, error) {
+	ptr, size, err := readAllC(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer C.free(ptr)
+
+	// fbcdcWriteCLOB takes a NUL-terminated string and would cut the
+	// text short.
+	if b.clob && bytes.IndexByte(unsafe.Slice((*byte)(ptr), size), 0) >= 0 {
+		return nil, nil, fmt.Errorf("can't bind CLOB text containing a NUL byte")
+	}
+
+	var handle *C.FBCBlobHandle
+	if b.clob {
+		handle = C.fbcdcWriteCLOB(dc.conn, (*C.char)(ptr))
+	} else {
+		handle = C.fbcdcWriteBLOB(dc.conn, ptr, C.uint(size))
+	}
+
+	if handle == nil {
+		return nil, nil, fmt.Errorf("writing %d bytes of LOB data failed", size)
+	}
+
+	return sqlLiteral(C.GoString(C.fbcbhDescription(handle))), handle, nil
+}
+
+// Write the Blob values among `args` to the server, replacing
+// them with literals referring to the written data.
+func (dc *Conn) writeLOBs(args []driver.NamedValue) ([]driver.NamedValue, lobHandles, error) {
+	var handles lobHandles
+	var written []driver.NamedValue
+
+	for i, arg := range args {
+		b, ok := arg.Value.(*Blob)
+		if !ok {
+			continue
+		}
+
+		if written == nil {
+			written = append([]driver.NamedValue(nil), args...)
+		}
+
+		lit, handle, err := dc.writeLOB(b)
+		if err != nil {
+			handles.release()
+			return nil, nil, err
+		}
+
+		handles = append(handles, handle)
+		written[i].Value = lit
+	}
+
+	if written == nil {
+		return args, nil, nil
+	}
+	return written, handles, nil
+}
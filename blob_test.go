@@ -0,0 +1,100 @@
+package frontbase
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReadAllC(t *testing.T) {
+	for _, size := range []int{0, 1, 64*1024 - 1, 64 * 1024, 200 * 1000} {
+		data := bytes.Repeat([]byte{'x'}, size)
+
+		ptr, n, err := readAllC(iotest.HalfReader(bytes.NewReader(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buf := newCBuffer(ptr, n)
+		if !bytes.Equal(data, buf.bytes()) {
+			t.Errorf("size %d: data differs after reading %d bytes", size, n)
+		}
+		buf.free()
+	}
+}
+
+func TestBlob_Scan(t *testing.T) {
+	var b Blob
+
+	if err := b.Scan([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := io.ReadAll(&b); string(data) != "hello" || b.IsClob() {
+		t.Errorf("expected BLOB hello, got %q", data)
+	}
+
+	if err := b.Scan("world"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := io.ReadAll(&b); string(data) != "world" || !b.IsClob() {
+		t.Errorf("expected CLOB world, got %q", data)
+	}
+
+	if err := b.Scan(nil); err == nil {
+		t.Error("expected scanning NULL to fail")
+	}
+}
+
+func TestQuery_blob(t *testing.T) {
+	tdb := createTempdbWithOptions(t, "lobs=blob")
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( b blob, c clob );")
+
+	data := bytes.Repeat([]byte{0xde, 0xad, 0xbe, 0xef}, 100000)
+	text := strings.Repeat("helo world ", 10000)
+	tdb.mustExec("insert into t0 values ( ?, ? );", NewBlob(bytes.NewReader(data)), NewClob(strings.NewReader(text)))
+
+	var b, c Blob
+	if err := tdb.db.QueryRow("select b, c from t0;").Scan(&b, &c); err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	defer c.Close()
+
+	if b.Len() != len(data) {
+		t.Errorf("expected %d bytes, got %d", len(data), b.Len())
+	}
+	if actual, _ := io.ReadAll(&b); !bytes.Equal(data, actual) {
+		t.Error("BLOB data differs")
+	}
+	if actual, _ := io.ReadAll(&c); string(actual) != text {
+		t.Error("CLOB text differs")
+	}
+}
+
+func TestQuery_blob_bytes(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( b blob, c clob );")
+	tdb.mustExec("insert into t0 values ( ?, ? );", NewBlob(strings.NewReader("abc")), NewClob(strings.NewReader("def")))
+
+	var b []byte
+	var c string
+	if err := tdb.db.QueryRow("select b, c from t0;").Scan(&b, &c); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "abc" || c != "def" {
+		t.Errorf("expected abc and def, got %q and %q", b, c)
+	}
+}
+
+func TestWriteLOB_clob_with_NUL(t *testing.T) {
+	_, _, err := (&Conn{}).writeLOB(NewClob(strings.NewReader("helo\x00world")))
+	if err == nil || err.Error() != "can't bind CLOB text containing a NUL byte" {
+		t.Errorf("expected the NUL byte to be rejected, got %v", err)
+	}
+}
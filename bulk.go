@@ -0,0 +1,193 @@
+package frontbase
+
+/*
+#include "clib.h"
+*/
+import "C"
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//
+// Bulk loading
+//
+
+// A RowSource supplies the rows for BulkLoad, one slice of column
+// values at a time. Next returns io.EOF when there are no more rows.
+type RowSource interface {
+	Next(ctx context.Context) ([]any, error)
+}
+
+// CSVRows returns a RowSource reading records from r. The fields
+// are bound as character strings, so the columns they are loaded
+// into must accept those.
+func CSVRows(r *csv.Reader) RowSource {
+	return csvRows{r}
+}
+
+type csvRows struct {
+	r *csv.Reader
+}
+
+func (src csvRows) Next(ctx context.Context) ([]any, error) {
+	record, err := src.r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	row := make([]any, len(record))
+	for i, field := range record {
+		row[i] = field
+	}
+	return row, nil
+}
+
+// ChanRows returns a RowSource receiving rows from ch until it is
+// closed.
+func ChanRows(ch <-chan []any) RowSource {
+	return chanRows(ch)
+}
+
+type chanRows <-chan []any
+
+func (src chanRows) Next(ctx context.Context) ([]any, error) {
+	select {
+	case row, ok := <-src:
+		if !ok {
+			return nil, io.EOF
+		}
+		return row, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// BulkLoadOptions configures BulkLoad. The zero value is usable.
+type BulkLoadOptions struct {
+	// Rows inserted per statement, defaults to 500.
+	BatchSize int
+
+	// Called after each batch with the number of rows loaded so far.
+	OnProgress func(rows int64)
+}
+
+const defaultBulkLoadBatchSize = 500
+
+// BulkLoad inserts the rows of `source` into the `columns` of
+// `table`, in batches of rows inserted by a single statement each.
+// It runs in a transaction on conn, see InNestedTx, so either all
+// rows are loaded or none. It returns the number of rows loaded.
+//
+// The table and column names are written into the statement as
+// they are: they must not come from untrusted input, and names
+// that need quoting must be given quoted.
+func BulkLoad(ctx context.Context, conn *sql.Conn, table string, columns []string, source RowSource, opts BulkLoadOptions) (int64, error) {
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("bulk load into %s: no columns", table)
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBulkLoadBatchSize
+	}
+
+	var loaded int64
+
+	err := InNestedTx(ctx, conn, func(ctx context.Context) error {
+		batch := make([][]any, 0, batchSize)
+
+		for done := false; !done; {
+			row, err := source.Next(ctx)
+			if errors.Is(err, io.EOF) {
+				done = true
+			} else if err != nil {
+				return fmt.Errorf("bulk load into %s: row %d: %w", table, loaded+int64(len(batch)), err)
+			} else {
+				batch = append(batch, row)
+			}
+
+			if len(batch) == batchSize || done && len(batch) > 0 {
+				err := conn.Raw(func(driverConn any) error {
+					dc, err := frontbaseConn(driverConn, "BulkLoad")
+					if err != nil {
+						return err
+					}
+					return dc.insertRows(ctx, table, columns, batch)
+				})
+				if err != nil {
+					return fmt.Errorf("bulk load into %s: rows %d to %d: %w", table, loaded, loaded+int64(len(batch))-1, err)
+				}
+
+				loaded += int64(len(batch))
+				batch = batch[:0]
+
+				if opts.OnProgress != nil {
+					opts.OnProgress(loaded)
+				}
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return loaded, nil
+}
+
+// Insert `rows` into `table` with a single statement.
+func (dc *Conn) insertRows(ctx context.Context, table string, columns []string, rows [][]any) error {
+	named := make([]driver.NamedValue, 0, len(rows)*len(columns))
+
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return fmt.Errorf("row %d has %d values, want %d", i, len(row), len(columns))
+		}
+
+		var err error
+		named, err = dc.appendNamedValues(named, row)
+		if err != nil {
+			return fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+
+	// Batches are of the same size but the last, so the statement
+	// text mostly hits the statement cache.
+	pstmt, err := dc.parse(insertRowsSQL(table, columns, len(rows)))
+	if err != nil {
+		return err
+	}
+
+	md, err := dc.execBound(ctx, pstmt, named)
+	if err != nil {
+		return err
+	}
+	C.fbcmdRelease(md)
+	return nil
+}
+
+// Return an INSERT statement with a VALUES list of `nrows` rows of
+// placeholders.
+func insertRowsSQL(table string, columns []string, nrows int) string {
+	placeholders := "( ?" + strings.Repeat(", ?", len(columns)-1) + " )"
+
+	sql := strings.Builder{}
+	fmt.Fprintf(&sql, "insert into %s ( %s ) values ", table, strings.Join(columns, ", "))
+	for i := 0; i < nrows; i++ {
+		if i > 0 {
+			sql.WriteString(", ")
+		}
+		sql.WriteString(placeholders)
+	}
+	sql.WriteString(";")
+
+	return sql.String()
+}
@@ -0,0 +1,152 @@
+package frontbase
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestInsertRowsSQL(t *testing.T) {
+	fixture := []struct {
+		columns  []string
+		nrows    int
+		expected string
+	}{
+		{[]string{"a"}, 1, "insert into t0 ( a ) values ( ? );"},
+		{[]string{"a", "b"}, 1, "insert into t0 ( a, b ) values ( ?, ? );"},
+		{[]string{"a", `"b c"`}, 3, `insert into t0 ( a, "b c" ) values ( ?, ? ), ( ?, ? ), ( ?, ? );`},
+	}
+
+	for _, tcase := range fixture {
+		actual := insertRowsSQL("t0", tcase.columns, tcase.nrows)
+		if actual != tcase.expected {
+			t.Errorf("expected %q, got %q", tcase.expected, actual)
+		}
+	}
+}
+
+func TestChanRows(t *testing.T) {
+	ch := make(chan []any, 2)
+	ch <- []any{1, "one"}
+	close(ch)
+
+	source := ChanRows(ch)
+
+	row, err := source.Next(context.Background())
+	if err != nil || len(row) != 2 || row[1] != "one" {
+		t.Errorf("expected the row sent, got %v, %v", row, err)
+	}
+
+	if _, err := source.Next(context.Background()); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ChanRows(make(chan []any)).Next(ctx); err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestBulkLoad(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( a character varying(10), b character varying(10) );")
+
+	ctx := context.Background()
+	conn, err := tdb.db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	source := CSVRows(csv.NewReader(strings.NewReader("1,one\n2,two\n3,three\n4,four\n5,five\n")))
+
+	var progress []int64
+	loaded, err := BulkLoad(ctx, conn, "t0", []string{"a", "b"}, source, BulkLoadOptions{
+		BatchSize:  2,
+		OnProgress: func(rows int64) { progress = append(progress, rows) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded != 5 {
+		t.Errorf("expected 5 rows loaded, got %d", loaded)
+	}
+	if len(progress) != 3 || progress[2] != 5 {
+		t.Errorf("expected progress after each of 3 batches, got %v", progress)
+	}
+
+	var count int32
+	if err := tdb.db.QueryRow("select count(*) from t0;").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 5 {
+		t.Errorf("expected 5 rows, got %d", count)
+	}
+
+	// A short row fails the load and rolls back all of it.
+	short := csv.NewReader(strings.NewReader("6,six\n7\n"))
+	short.FieldsPerRecord = -1
+	source = CSVRows(short)
+
+	if _, err := BulkLoad(ctx, conn, "t0", []string{"a", "b"}, source, BulkLoadOptions{}); err == nil {
+		t.Error("expected the short row to fail the load")
+	}
+
+	if err := tdb.db.QueryRow("select count(*) from t0;").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 5 {
+		t.Errorf("expected the failed load to be rolled back, got %d rows", count)
+	}
+}
+
+func TestBulkLoad_in_transaction(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( a character varying(10), b character varying(10) );")
+
+	ctx := context.Background()
+	conn, err := tdb.db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Loaded in a savepoint of the transaction, and rolled back
+	// with it.
+	source := CSVRows(csv.NewReader(strings.NewReader("1,one\n2,two\n")))
+	if _, err := BulkLoad(ctx, conn, "t0", []string{"a", "b"}, source, BulkLoadOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var count int32
+	if err := tx.QueryRow("select count(*) from t0;").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 rows in the transaction, got %d", count)
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tdb.db.QueryRow("select count(*) from t0;").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("expected the load to be rolled back, got %d rows", count)
+	}
+}
@@ -0,0 +1,149 @@
+package frontbase
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
+
+//
+// Civil dates and times of day
+//
+
+// A Date is a calendar date without a time zone, the Go
+// counterpart of a DATE column. It binds as a DATE literal.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// DateOf returns the Date of t, in t's location.
+func DateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// ParseDate parses a date on the form "2006-01-02".
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return Date{}, err
+	}
+	return DateOf(t), nil
+}
+
+// In returns the time at midnight of d in loc.
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+func (d Date) String() string {
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// prepared.Literal
+func (d Date) SQLLiteral() string {
+	return fmt.Sprintf("DATE '%s'", d)
+}
+
+// driver.Valuer
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// sql.Scanner
+func (d *Date) Scan(src any) error {
+	var err error
+
+	switch v := src.(type) {
+	case time.Time:
+		*d = DateOf(v)
+	case string:
+		*d, err = ParseDate(v)
+	case []byte:
+		*d, err = ParseDate(string(v))
+	case nil:
+		err = fmt.Errorf("can't scan NULL into Date, use sql.Null[Date]")
+	default:
+		err = fmt.Errorf("can't scan %T into Date", src)
+	}
+
+	return err
+}
+
+// A TimeOfDay is a wall clock time without a date or a time zone,
+// the Go counterpart of a TIME column. It binds as a TIME literal.
+type TimeOfDay struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// TimeOfDayOf returns the TimeOfDay of t, in t's location.
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	return TimeOfDay{
+		Hour:       t.Hour(),
+		Minute:     t.Minute(),
+		Second:     t.Second(),
+		Nanosecond: t.Nanosecond(),
+	}
+}
+
+// ParseTimeOfDay parses a time on the form "15:04:05", optionally
+// followed by a fraction of a second.
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	t, err := time.Parse("15:04:05.999999999", s)
+	if err != nil {
+		return TimeOfDay{}, err
+	}
+	return TimeOfDayOf(t), nil
+}
+
+// Duration returns the time elapsed since midnight.
+func (tod TimeOfDay) Duration() time.Duration {
+	return time.Duration(tod.Hour)*time.Hour +
+		time.Duration(tod.Minute)*time.Minute +
+		time.Duration(tod.Second)*time.Second +
+		time.Duration(tod.Nanosecond)
+}
+
+func (tod TimeOfDay) String() string {
+	s := fmt.Sprintf("%02d:%02d:%02d", tod.Hour, tod.Minute, tod.Second)
+	if tod.Nanosecond != 0 {
+		s += "." + strings.TrimRight(fmt.Sprintf("%09d", tod.Nanosecond), "0")
+	}
+	return s
+}
+
+// prepared.Literal
+func (tod TimeOfDay) SQLLiteral() string {
+	return fmt.Sprintf("TIME '%s'", tod)
+}
+
+// driver.Valuer
+func (tod TimeOfDay) Value() (driver.Value, error) {
+	return tod.String(), nil
+}
+
+// sql.Scanner
+func (tod *TimeOfDay) Scan(src any) error {
+	var err error
+
+	switch v := src.(type) {
+	case time.Time:
+		*tod = TimeOfDayOf(v)
+	case string:
+		*tod, err = ParseTimeOfDay(v)
+	case []byte:
+		*tod, err = ParseTimeOfDay(string(v))
+	case nil:
+		err = fmt.Errorf("can't scan NULL into TimeOfDay, use sql.Null[TimeOfDay]")
+	default:
+		err = fmt.Errorf("can't scan %T into TimeOfDay", src)
+	}
+
+	return err
+}
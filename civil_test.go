@@ -0,0 +1,60 @@
+package frontbase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDate(t *testing.T) {
+	d, err := ParseDate("2024-03-16")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (Date{2024, time.March, 16}); d != expected {
+		t.Errorf("expected %v, got %v", expected, d)
+	}
+	if literal := d.SQLLiteral(); literal != "DATE '2024-03-16'" {
+		t.Errorf("expected DATE '2024-03-16', got %s", literal)
+	}
+
+	var scanned Date
+	if err := scanned.Scan(time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)); err != nil || scanned != d {
+		t.Errorf("expected %v, got %v, %v", d, scanned, err)
+	}
+
+	if _, err := ParseDate("2024-13-01"); err == nil {
+		t.Error("expected an invalid month to fail")
+	}
+}
+
+func TestTimeOfDay(t *testing.T) {
+	fixture := []struct {
+		text     string
+		expected TimeOfDay
+		literal  string
+	}{
+		{"00:00:00", TimeOfDay{}, "TIME '00:00:00'"},
+		{"13:14:15", TimeOfDay{13, 14, 15, 0}, "TIME '13:14:15'"},
+		{"13:14:15.5", TimeOfDay{13, 14, 15, 500000000}, "TIME '13:14:15.5'"},
+		{"23:59:59.000001", TimeOfDay{23, 59, 59, 1000}, "TIME '23:59:59.000001'"},
+	}
+
+	for _, tcase := range fixture {
+		tod, err := ParseTimeOfDay(tcase.text)
+		if err != nil {
+			t.Errorf("case %q unexpected error '%v'", tcase.text, err)
+			continue
+		}
+		if tod != tcase.expected {
+			t.Errorf("case %q expected %#v but got %#v", tcase.text, tcase.expected, tod)
+		}
+		if literal := tod.SQLLiteral(); literal != tcase.literal {
+			t.Errorf("case %q expected %s but got %s", tcase.text, tcase.literal, literal)
+		}
+	}
+
+	if d := (TimeOfDay{1, 2, 3, 4}).Duration(); d != time.Hour+2*time.Minute+3*time.Second+4 {
+		t.Errorf("unexpected duration %v", d)
+	}
+}
@@ -3,8 +3,8 @@
 #include <stdlib.h>
 #include "clib.h"
 
-FBCDatabaseConnection *GoFBOpen(const char *url) {
-	FBCMetaData *md = fbcdcConnectToURL(url,"","_system","","sid");
+FBCDatabaseConnection *GoFBOpen(const char *url, const char *databasePassword, const char *user, const char *userPassword, const char *session) {
+	FBCMetaData *md = fbcdcConnectToURL(url, databasePassword, user, userPassword, session);
 
 	if (fbcmdErrorsFound(md)) {
 		fprintf(stderr, "%s: open failed\n", url);
@@ -26,6 +26,14 @@ void GoFBClose(FBCDatabaseConnection *connection) {
 	fbcdcRelease(connection);
 }
 
+void GoFBCancel(FBCDatabaseConnection *connection) {
+	if (connection == NULL) return;
+
+	// Closing the connection makes a statement executing on
+	// another thread return; the connection is unusable after.
+	fbcdcClose(connection);
+}
+
 int GoFBPing(FBCDatabaseConnection *connection) {
 	if (connection == NULL) return 0;
 
@@ -80,18 +88,110 @@ int GoFBColumnSizeBit(FBCColumn *col) {
 	return col->bit.size;
 }
 
-void GoFBColumnValueTimestamp(FBCColumn *col, struct GoFBTimestampValue *res) {
-	if (res == NULL) return;
-	double secs = col->rawTimestamp.seconds;
+FBCBlobHandle *GoFBColumnValueBlobHandle(FBCColumn *col) {
+	return *(FBCBlobHandle **)col;
+}
+
+// The returned data is malloc'ed and owned by the caller.
+// Returns 0 when the data could not be read.
+int GoFBReadBLOB(FBCDatabaseConnection *connection, FBCColumn *col, void **data, unsigned int *size) {
+	FBCBlobHandle *handle = GoFBColumnValueBlobHandle(col);
+	*data = NULL;
+	*size = 0;
+
+	if (handle == NULL) {
+		return 0;
+	}
+
+	*size = fbcbhBlobSize(handle);
+	if (*size == 0) {
+		return 1;
+	}
+
+	*data = fbcdcReadBLOB(connection, handle);
+	return *data != NULL;
+}
+
+// The returned string is malloc'ed and owned by the caller, NULL
+// when the text could not be read.
+char *GoFBReadCLOB(FBCDatabaseConnection *connection, FBCColumn *col) {
+	FBCBlobHandle *handle = GoFBColumnValueBlobHandle(col);
+	if (handle == NULL) {
+		return NULL;
+	}
+	return fbcdcReadCLOB(connection, handle);
+}
+
+double GoFBColumnValueSeconds(FBCColumn *col) {
+	return col->rawTimestamp.seconds;
+}
+
+// Year-month intervals are a number of months, day-time intervals
+// a number of seconds like TIME values.
+int64_t GoFBColumnValueYearMonth(FBCColumn *col) {
+	return col->integer;
+}
+
+// The offset of a TIMESTAMP or TIME WITH TIME ZONE value, in
+// seconds east of UTC. The seconds of the value are in UTC.
+int GoFBColumnValueTimeZoneOffset(FBCColumn *col) {
+	return col->rawTimestamp.timeZoneOffset;
+}
+
+// Execute `sql` like fbcdcExecuteSQL. With a positive `rowCount`
+// at most that many rows come with the result, the rest are fetched
+// with GoFBFetch; the connection's row fetch count is restored after.
+FBCMetaData *GoFBExecuteSQL(FBCDatabaseConnection *connection, const char *sql, unsigned int length, unsigned int options, int rowCount) {
+	if (rowCount <= 0) {
+		return fbcdcExecuteSQL(connection, sql, length, options);
+	}
+
+	int previous = fbcdcRowFetchCount(connection);
+	fbcdcSetRowFetchCount(connection, rowCount);
+	FBCMetaData *md = fbcdcExecuteSQL(connection, sql, length, options);
+	fbcdcSetRowFetchCount(connection, previous);
+	return md;
+}
 
-	res->secs = (int64_t)secs;
+// Fetch the next block of at most `rowCount` rows of the result
+// `md` from the server, or NULL when it has no rows left to fetch.
+// The returned block is released by the caller.
+FBCMetaData *GoFBFetch(FBCDatabaseConnection *connection, FBCMetaData *md, int rowCount) {
+	const char *fetchHandle = fbcmdFetchHandle(md);
+	if (fetchHandle == NULL) {
+		return NULL;
+	}
+	return fbcdcFetch(connection, rowCount, fetchHandle);
+}
 
-	double fraction = secs - res->secs;
-	res->nsecs = (int64_t)(fraction * 1000000000.0);
+// The 1-based position in the SQL text that error `i` refers to,
+// or 0 when it refers to none.
+unsigned GoFBErrorPositionAtIndex(FBCErrorMetaData *emd, unsigned int i) {
+	return fbcemdErrorPositionAtIndex(emd, i);
+}
 
-	res->secs += 978307200;
+// The severity of error `i`, numbered like frontbase.Severity:
+// 0 for errors and 1 for warnings.
+int GoFBErrorSeverityAtIndex(FBCErrorMetaData *emd, unsigned int i) {
+	return fbcemdErrorIsWarningAtIndex(emd, i) ? 1 : 0;
+}
+
+// The key generated by the UNIQUE default of the table for the row
+// inserted by the statement of `md`, as decimal digits, or NULL
+// when none was. FBCAccess reports it as the row index of the
+// statement, as PHP's fbsql_insert_id reads it; it is read at the
+// width and signedness fbcmdRowIndex is declared with, so that no
+// key is narrowed. The returned string is malloc'ed and owned by
+// the caller.
+char *GoFBGeneratedKey(FBCMetaData *md) {
+	__typeof__(fbcmdRowIndex(md)) key = fbcmdRowIndex(md);
+	if (key <= 0) {
+		return NULL;
+	}
 
-	if (res->nsecs < 0) {
-		res->nsecs = 1000000000 + res->nsecs;
+	char *text = malloc(24);
+	if (text != NULL) {
+		snprintf(text, 24, "%llu", (unsigned long long)key);
 	}
+	return text;
 }
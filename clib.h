@@ -2,11 +2,14 @@
 #include <stdio.h>
 #include <stdlib.h>
 #include <stdint.h>
+#include <string.h>
 
-FBCDatabaseConnection *GoFBOpen(const char *url);
+FBCDatabaseConnection *GoFBOpen(const char *url, const char *databasePassword, const char *user, const char *userPassword, const char *session);
 
 void GoFBClose(FBCDatabaseConnection *connection);
 
+void GoFBCancel(FBCDatabaseConnection *connection);
+
 int GoFBPing(FBCDatabaseConnection *connection);
 
 FBCColumn *GoFBColumnAtIndex(FBCRow *row, unsigned int i);
@@ -23,9 +26,18 @@ char *GoFBColumnValueChar(FBCColumn *col);
 unsigned char *GoFBColumnValueBit(FBCColumn *col);
 int GoFBColumnSizeBit(FBCColumn *col);
 
-struct GoFBTimestampValue {
-  int64_t secs;
-  int64_t nsecs;
-};
+FBCBlobHandle *GoFBColumnValueBlobHandle(FBCColumn *col);
+int GoFBReadBLOB(FBCDatabaseConnection *connection, FBCColumn *col, void **data, unsigned int *size);
+char *GoFBReadCLOB(FBCDatabaseConnection *connection, FBCColumn *col);
+
+double GoFBColumnValueSeconds(FBCColumn *col);
+int64_t GoFBColumnValueYearMonth(FBCColumn *col);
+int GoFBColumnValueTimeZoneOffset(FBCColumn *col);
+
+FBCMetaData *GoFBExecuteSQL(FBCDatabaseConnection *connection, const char *sql, unsigned int length, unsigned int options, int rowCount);
+FBCMetaData *GoFBFetch(FBCDatabaseConnection *connection, FBCMetaData *md, int rowCount);
+
+unsigned GoFBErrorPositionAtIndex(FBCErrorMetaData *emd, unsigned int i);
+int GoFBErrorSeverityAtIndex(FBCErrorMetaData *emd, unsigned int i);
 
-void GoFBColumnValueTimestamp(FBCColumn *col, struct GoFBTimestampValue *res);
+char *GoFBGeneratedKey(FBCMetaData *md);
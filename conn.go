@@ -10,6 +10,9 @@ import (
 	"database/sql/driver"
 	"fmt"
 	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
 	"unsafe"
 
 	"github.com/Oops-AB/go-frontbase/prepared"
@@ -20,16 +23,30 @@ import (
 //
 
 type Conn struct {
-	conn *C.FBCDatabaseConnection
-	inTx bool
+	conn      *C.FBCDatabaseConnection
+	inTx      bool
+	nestLevel int  // savepoints established by InNestedTx
+	bad       bool // torn down by a cancelled context or a lost connection
+
+	sessionZone   *time.Location // nil when the session time zone is not known
+	location      *time.Location // of decoded timestamps
+	exactDecimals bool
+	blobLOBs      bool
+	fetchSize     int // rows fetched per round trip, 0 for the FBCAccess default
+
+	stmts *prepared.Cache // shared with the connector's other connections, or nil
 }
 
-func (dc *Conn) setUTC() error {
-	md, err := dc.exec("SET TIME ZONE 'UTC';", true, false)
+func (dc *Conn) setTimeZone(ctx context.Context, zone string) error {
+	query := fmt.Sprintf("SET TIME ZONE '%s';", strings.Replace(zone, `'`, `''`, -1))
+	md, err := dc.exec(ctx, query, true, false)
 	if err != nil {
 		return err
 	}
 	C.fbcmdRelease(md)
+
+	// The zone was validated by checkTimeZone.
+	dc.sessionZone, _ = time.LoadLocation(zone)
 	return nil
 }
 
@@ -76,7 +93,7 @@ func (dc *Conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx,
 	}
 
 	query := fmt.Sprintf("set transaction isolation level %s, %s;", isolation, readOrWrite)
-	md, err := dc.exec(query, true, false)
+	md, err := dc.exec(ctx, query, true, false)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +106,11 @@ func (dc *Conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx,
 }
 
 func (dc *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
-	prepped, err := prepared.ParseSQL(query)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	prepped, err := dc.parse(query)
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +122,14 @@ func (dc *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt,
 	}, nil
 }
 
+// Parse `query`, through the statement cache when there is one.
+func (dc *Conn) parse(query string) (*prepared.Stmt, error) {
+	if dc.stmts == nil {
+		return prepared.ParseSQL(query)
+	}
+	return dc.stmts.Get(query)
+}
+
 func (dc *Conn) Prepare(query string) (driver.Stmt, error) {
 	return dc.PrepareContext(context.Background(), query)
 }
@@ -127,30 +156,47 @@ func (tx Tx) Rollback() error {
 	return tx.dc.rollback()
 }
 
+// Commit the transaction. Whatever the outcome the connection is
+// out of the transaction afterwards: when the commit fails the
+// transaction is rolled back, and when that fails too the
+// connection is discarded.
 func (dc *Conn) commit() error {
-	query := fmt.Sprintf("commit;")
-	md, err := dc.exec(query, true, false)
+	defer dc.endTx()
+
+	md, err := dc.exec(context.Background(), "commit;", true, false)
 	if err != nil {
+		if md, rbErr := dc.exec(context.Background(), "rollback;", true, false); rbErr != nil {
+			dc.bad = true
+		} else {
+			C.fbcmdRelease(md)
+		}
 		return err
 	}
-	defer C.fbcmdRelease(md)
+	C.fbcmdRelease(md)
 
-	dc.inTx = false
 	return nil
 }
 
+// Roll the transaction back. When that fails the connection is
+// discarded, as the state of the transaction is unknown.
 func (dc *Conn) rollback() error {
-	query := fmt.Sprintf("rollback;")
-	md, err := dc.exec(query, true, false)
+	defer dc.endTx()
+
+	md, err := dc.exec(context.Background(), "rollback;", true, false)
 	if err != nil {
+		dc.bad = true
 		return err
 	}
-	defer C.fbcmdRelease(md)
+	C.fbcmdRelease(md)
 
-	dc.inTx = false
 	return nil
 }
 
+func (dc *Conn) endTx() {
+	dc.inTx = false
+	dc.nestLevel = 0
+}
+
 //
 // Optional
 //
@@ -163,21 +209,80 @@ func (dc *Conn) Ping(ctx context.Context) error {
 	return nil
 }
 
+// NamedValueChecker
+func (dc *Conn) CheckNamedValue(nv *driver.NamedValue) error {
+	value, err := dc.convertValue(nv.Value)
+	if err != nil {
+		if nv.Name != "" {
+			return fmt.Errorf("argument @%s: %w", nv.Name, err)
+		}
+		return fmt.Errorf("argument %d: %w", nv.Ordinal, err)
+	}
+
+	nv.Value = value
+	return nil
+}
+
 // SessionResetter
 func (dc *Conn) ResetSession(ctx context.Context) error {
+	if dc.bad {
+		return driver.ErrBadConn
+	}
 	return nil
 }
 
+// ExecerContext
+func (dc *Conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	pstmt, err := dc.parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := dc.execBound(ctx, pstmt, args)
+	if err != nil {
+		return nil, err
+	}
+	defer C.fbcmdRelease(md)
+
+	return newResult(md), nil
+}
+
+// QueryerContext
+func (dc *Conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	pstmt, err := dc.parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	md, err := dc.execBound(ctx, pstmt, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRows(ctx, md, dc), nil
+}
+
 // Validator
 func (dc *Conn) IsValid() bool {
-	return true
+	return !dc.bad
 }
 
 //
 // Internal
 //
 
-func (dc *Conn) exec(sql string, commit bool, warn bool) (*C.FBCMetaData, error) {
+// Execute `sql` on the connection. If `ctx` is done before the
+// server responds the connection is torn down, marked bad and
+// the context's error is returned.
+func (dc *Conn) exec(ctx context.Context, sql string, commit bool, warn bool) (*C.FBCMetaData, error) {
+	if dc.bad {
+		return nil, driver.ErrBadConn
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	csql := C.CString(sql)
 	defer C.free(unsafe.Pointer(csql))
 
@@ -188,25 +293,96 @@ func (dc *Conn) exec(sql string, commit bool, warn bool) (*C.FBCMetaData, error)
 		commitFlags = 2 // FBCDCCommit
 	}
 
-	md := C.fbcdcExecuteSQL(dc.conn, csql, C.uint(clen), C.uint(commitFlags))
+	stopWatching := dc.watchCancel(ctx)
+	fetchSize := C.int(fetchSizeFor(ctx, dc))
+	md := C.GoFBExecuteSQL(dc.conn, csql, C.uint(clen), C.uint(commitFlags), fetchSize)
+
+	if cancelled := stopWatching(); cancelled {
+		dc.bad = true
+
+		// The statement may have completed before the connection
+		// was torn down; report that rather than the cancellation,
+		// unless there are rows that can no longer be fetched.
+		if md != nil && C.fbcmdErrorsFound(md) == 0 && !newResult(md).isSelect() {
+			return md, nil
+		}
+
+		if md != nil {
+			C.fbcmdRelease(md)
+		}
+		return nil, ctx.Err()
+	}
 
 	if md == nil && C.fbcdcConnected(dc.conn) == 0 {
-		C.GoFBClose(dc.conn)
-		return nil, fmt.Errorf("conn %p: no database connection", dc)
+		C.GoFBCancel(dc.conn)
+		dc.bad = true
+		return nil, ErrConnectionLost
 	}
 
 	if C.fbcmdErrorsFound(md) != 0 {
 		defer C.fbcmdRelease(md)
+		return nil, metaDataErrors(md)
+	}
 
-		emd := C.fbcmdErrorMetaData(md)
-		defer C.fbcemdRelease(emd)
+	return md, nil
+}
 
-		all := C.fbcemdAllErrorMessages(emd)
-		defer C.fbcemdReleaseMessage(all)
+// Bind `args` to `pstmt`, writing any LOBs among them first, and
+// execute the result.
+func (dc *Conn) execBound(ctx context.Context, pstmt *prepared.Stmt, args []driver.NamedValue) (*C.FBCMetaData, error) {
+	args, lobs, err := dc.writeLOBs(args)
+	if err != nil {
+		return nil, err
+	}
+	defer lobs.release()
 
-		msg := C.GoString(all)
-		return nil, fmt.Errorf("conn %p: execute SQL failed:\n%v", dc, msg)
+	sql, err := pstmt.BindNamed(args)
+	if err != nil {
+		return nil, err
 	}
 
-	return md, nil
+	return dc.exec(ctx, sql, !dc.inTx, false)
+}
+
+// Start watching `ctx` while a statement executes. When the
+// context is done the in-flight statement is aborted by tearing
+// down the connection. The returned function stops the watch and
+// reports whether the connection was torn down; once it has been
+// called the connection is left alone.
+func (dc *Conn) watchCancel(ctx context.Context) func() bool {
+	if ctx.Done() == nil {
+		return func() bool { return false }
+	}
+
+	const (
+		running int32 = iota
+		finished
+		cancelled
+	)
+
+	conn := dc.conn
+	state := new(atomic.Int32)
+	finish := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		select {
+		case <-ctx.Done():
+			if state.CompareAndSwap(running, cancelled) {
+				C.GoFBCancel(conn)
+			}
+		case <-finish:
+		}
+	}()
+
+	return func() bool {
+		if state.CompareAndSwap(running, finished) {
+			close(finish)
+			return false
+		}
+		<-done
+		return true
+	}
 }
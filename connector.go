@@ -3,21 +3,54 @@ package frontbase
 import (
 	"context"
 	"database/sql/driver"
+
+	"github.com/Oops-AB/go-frontbase/prepared"
 )
 
+// A Connector opens connections using a Config parsed once,
+// for use with sql.OpenDB. Its connections share a cache of
+// parsed statements.
 type Connector struct {
-	name   string
+	cfg    Config
 	driver *Driver
+	stmts  *prepared.Cache // nil when disabled
+}
+
+// NewConnector returns a connector for the supplied Config.
+func NewConnector(cfg Config) (driver.Connector, error) {
+	return newConnector(cfg, &Driver{}), nil
 }
 
-func (cnct Connector) Connect(context.Context) (driver.Conn, error) {
-	return cnct.driver.open(cnct.name)
+func newConnector(cfg Config, drv *Driver) Connector {
+	cnct := Connector{
+		cfg:    cfg,
+		driver: drv,
+	}
+
+	if size := cfg.withDefaults().StatementCache; size > 0 {
+		cnct.stmts = prepared.NewCache(size)
+	}
+
+	return cnct
 }
 
-func (cnct Connector) Driver() *Driver {
+func (cnct Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	return cnct.driver.open(ctx, cnct.cfg, cnct.stmts)
+}
+
+func (cnct Connector) Driver() driver.Driver {
 	return cnct.driver
 }
 
 func (cnct Connector) Close() error {
 	return nil
 }
+
+// StatementCacheStats returns the hit and miss counts of the
+// statement cache shared by the connector's connections.
+func (cnct Connector) StatementCacheStats() prepared.CacheStats {
+	if cnct.stmts == nil {
+		return prepared.CacheStats{}
+	}
+	return cnct.stmts.Stats()
+}
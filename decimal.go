@@ -0,0 +1,154 @@
+package frontbase
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+//
+// Exact DECIMAL and NUMERIC values
+//
+
+// A Decimal is an exact decimal number: an unscaled integer and a
+// scale, the number of digits after the decimal point. The zero
+// value is 0.
+//
+// Decimal implements sql.Scanner and driver.Valuer. It is bound as
+// an exact numeric literal. DECIMAL and NUMERIC columns are decoded
+// into it when the connection is opened with the DSN option
+// decimals=exact; the server hands out a double, which formatted with
+// the column's scale is exact up to 15 significant digits.
+type Decimal struct {
+	unscaled *big.Int // nil means 0
+	scale    int32
+}
+
+// NewDecimal returns the Decimal unscaled * 10^-scale.
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return Decimal{
+		unscaled: big.NewInt(unscaled),
+		scale:    scale,
+	}
+}
+
+// ParseDecimal parses a decimal number such as "-123.4500".
+// The scale is the number of digits after the decimal point.
+func ParseDecimal(s string) (Decimal, error) {
+	digits := s
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		digits = digits[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(digits, ".")
+	if intPart == "" && fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	unscaled, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+
+	if s[0] == '-' {
+		unscaled.Neg(unscaled)
+	}
+
+	return Decimal{
+		unscaled: unscaled,
+		scale:    int32(len(fracPart)),
+	}, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Unscaled returns the unscaled integer value of d.
+func (d Decimal) Unscaled() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(d.unscaled)
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Rat returns d as a rational number.
+func (d Decimal) Rat() *big.Rat {
+	if d.scale < 0 {
+		factor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-d.scale)), nil)
+		return new(big.Rat).SetInt(factor.Mul(factor, d.Unscaled()))
+	}
+
+	denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d.scale)), nil)
+	return new(big.Rat).SetFrac(d.Unscaled(), denom)
+}
+
+// Float64 returns the float64 value nearest to d.
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+func (d Decimal) String() string {
+	digits := d.Unscaled().String()
+
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+
+	if d.scale <= 0 {
+		return sign + digits + strings.Repeat("0", int(-d.scale))
+	}
+
+	if pad := int(d.scale) - len(digits) + 1; pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+
+	point := len(digits) - int(d.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// prepared.Literal
+func (d Decimal) SQLLiteral() string {
+	return d.String()
+}
+
+// driver.Valuer
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// sql.Scanner
+func (d *Decimal) Scan(src any) error {
+	var err error
+
+	switch v := src.(type) {
+	case string:
+		*d, err = ParseDecimal(v)
+	case []byte:
+		*d, err = ParseDecimal(string(v))
+	case int64:
+		*d = NewDecimal(v, 0)
+	case float64:
+		*d, err = ParseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+	case nil:
+		err = fmt.Errorf("can't scan NULL into Decimal, use sql.Null[Decimal]")
+	default:
+		err = fmt.Errorf("can't scan %T into Decimal", src)
+	}
+
+	return err
+}
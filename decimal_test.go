@@ -0,0 +1,98 @@
+package frontbase
+
+import (
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	fixture := []struct {
+		text     string
+		expected string
+		scale    int32
+		failure  bool
+	}{
+		{"0", "0", 0, false},
+		{"1.20", "1.20", 2, false},
+		{"-1.20", "-1.20", 2, false},
+		{"+3", "3", 0, false},
+		{".5", "0.5", 1, false},
+		{"-0.001", "-0.001", 3, false},
+		{"12345678901234567890.123456789", "12345678901234567890.123456789", 9, false},
+		{"", "", 0, true},
+		{"-", "", 0, true},
+		{".", "", 0, true},
+		{"1.2.3", "", 0, true},
+		{"1e3", "", 0, true},
+	}
+
+	for _, tcase := range fixture {
+		d, err := ParseDecimal(tcase.text)
+
+		if tcase.failure {
+			if err == nil {
+				t.Errorf("case %q expected an error, got %s", tcase.text, d)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("case %q unexpected error '%v'", tcase.text, err)
+			continue
+		}
+
+		if d.String() != tcase.expected || d.Scale() != tcase.scale {
+			t.Errorf("case %q expected %s scale %d but got %s scale %d", tcase.text, tcase.expected, tcase.scale, d, d.Scale())
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	fixture := []struct {
+		decimal  Decimal
+		expected string
+		float    float64
+	}{
+		{Decimal{}, "0", 0},
+		{NewDecimal(5, 3), "0.005", 0.005},
+		{NewDecimal(-123456, 2), "-1234.56", -1234.56},
+		{NewDecimal(12, -2), "1200", 1200},
+	}
+
+	for _, tcase := range fixture {
+		if actual := tcase.decimal.String(); actual != tcase.expected {
+			t.Errorf("expected %s but got %s", tcase.expected, actual)
+		}
+		if actual := tcase.decimal.Float64(); actual != tcase.float {
+			t.Errorf("expected %v but got %v", tcase.float, actual)
+		}
+	}
+}
+
+func TestDecimal_Scan(t *testing.T) {
+	fixture := []struct {
+		src      any
+		expected string
+		failure  bool
+	}{
+		{"12.50", "12.50", false},
+		{[]byte("-0.25"), "-0.25", false},
+		{int64(42), "42", false},
+		{float64(1.5), "1.5", false},
+		{nil, "", true},
+		{true, "", true},
+	}
+
+	for _, tcase := range fixture {
+		var d Decimal
+		err := d.Scan(tcase.src)
+
+		if tcase.failure != (err != nil) {
+			t.Errorf("case %#v unexpected error state '%v'", tcase.src, err)
+			continue
+		}
+
+		if !tcase.failure && d.String() != tcase.expected {
+			t.Errorf("case %#v expected %s but got %s", tcase.src, tcase.expected, d)
+		}
+	}
+}
@@ -12,16 +12,21 @@ import (
 	"fmt"
 	"runtime"
 	"unsafe"
+
+	"github.com/Oops-AB/go-frontbase/prepared"
 )
 
 type Driver struct {
 }
 
-func (drv *Driver) OpenConnector(name string) (Connector, error) {
-	return Connector{
-		name:   name,
-		driver: drv,
-	}, nil
+// DriverContext
+func (drv *Driver) OpenConnector(name string) (driver.Connector, error) {
+	cfg, err := ParseDSN(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return newConnector(cfg, drv), nil
 }
 
 func (drv *Driver) Open(name string) (driver.Conn, error) {
@@ -33,22 +38,43 @@ func (drv *Driver) Open(name string) (driver.Conn, error) {
 	return connector.Connect(context.Background())
 }
 
-func (drv *Driver) open(name string) (driver.Conn, error) {
-	cname := C.CString(name)
-	defer C.free(unsafe.Pointer(cname))
+func (drv *Driver) open(ctx context.Context, cfg Config, stmts *prepared.Cache) (driver.Conn, error) {
+	cfg = cfg.withDefaults()
 
-	conn := C.GoFBOpen(cname)
+	if err := checkTimeZone(cfg.TimeZone); err != nil {
+		return nil, err
+	}
+
+	curl := C.CString(cfg.URL)
+	defer C.free(unsafe.Pointer(curl))
+	cdbpassword := C.CString(cfg.DatabasePassword)
+	defer C.free(unsafe.Pointer(cdbpassword))
+	cuser := C.CString(cfg.User)
+	defer C.free(unsafe.Pointer(cuser))
+	cpassword := C.CString(cfg.Password)
+	defer C.free(unsafe.Pointer(cpassword))
+	csession := C.CString(cfg.Session)
+	defer C.free(unsafe.Pointer(csession))
+
+	conn := C.GoFBOpen(curl, cdbpassword, cuser, cpassword, csession)
 	if conn == nil {
-		return nil, fmt.Errorf("drv %p: unable to open connection to '%s'", drv, name)
+		return nil, fmt.Errorf("drv %p: unable to open connection to '%s' as '%s'", drv, cfg.URL, cfg.User)
 	}
 
 	var newDrvConn = &Conn{
-		conn: conn,
+		conn:          conn,
+		location:      cfg.Location,
+		exactDecimals: cfg.ExactDecimals,
+		blobLOBs:      cfg.BlobLOBs,
+		fetchSize:     cfg.FetchSize,
+		stmts:         stmts,
 	}
 
-	if err := newDrvConn.setUTC(); err != nil {
-		newDrvConn.Close()
-		return nil, err
+	if cfg.TimeZone != noTimeZone {
+		if err := newDrvConn.setTimeZone(ctx, cfg.TimeZone); err != nil {
+			newDrvConn.Close()
+			return nil, err
+		}
 	}
 
 	_, file, line, _ := runtime.Caller(1)
@@ -0,0 +1,112 @@
+package frontbase
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Oops-AB/go-frontbase/prepared"
+)
+
+//
+// Converting arguments into values the statements can bind
+//
+
+// An SQL literal ready to be spliced into a statement.
+type sqlLiteral string
+
+func (lit sqlLiteral) SQLLiteral() string {
+	return string(lit)
+}
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[reflect.Type]func(any) (string, error){}
+)
+
+// RegisterEncoder registers how arguments of type T are bound:
+// `encode` returns the SQL literal for a value, e.g. "'abc'" or
+// "CAST('1,2' AS ...)". It takes precedence over driver.Valuer.
+// Registering a type again replaces its encoder.
+func RegisterEncoder[T any](encode func(T) (string, error)) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	encoders[reflect.TypeFor[T]()] = func(v any) (string, error) {
+		return encode(v.(T))
+	}
+}
+
+func lookupEncoder(v any) func(any) (string, error) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+
+	return encoders[reflect.TypeOf(v)]
+}
+
+var valuerType = reflect.TypeFor[driver.Valuer]()
+
+// Convert an argument into a value prepared.Stmt can bind, or
+// return an error describing why it can't be.
+func (dc *Conn) convertValue(v any) (any, error) {
+	if encode := lookupEncoder(v); encode != nil {
+		literal, err := encode(v)
+		if err != nil {
+			return nil, err
+		}
+		return sqlLiteral(literal), nil
+	}
+
+	switch x := v.(type) {
+	case prepared.Literal:
+		// e.g. Decimal, which encode themselves
+		return x, nil
+	case *Blob:
+		// written to the server before the statement executes, see writeLOBs
+		return x, nil
+	case float32, uint64:
+		// bound exactly rather than as float64 and int64
+		return x, nil
+	case time.Time:
+		return dc.convertTime(x), nil
+	case driver.Valuer:
+		rv := reflect.ValueOf(x)
+		if rv.Kind() == reflect.Pointer && rv.IsNil() && rv.Type().Elem().Implements(valuerType) {
+			// A nil pointer to a type with a value receiver.
+			return nil, nil
+		}
+
+		value, err := x.Value()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := value.(driver.Valuer); ok {
+			return nil, fmt.Errorf("%T.Value returned another driver.Valuer, %T", x, value)
+		}
+		return dc.convertValue(value)
+	}
+
+	value, err := driver.DefaultParameterConverter.ConvertValue(v)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := value.(time.Time); ok {
+		return dc.convertTime(t), nil
+	}
+	return value, nil
+}
+
+// Timestamps are encoded in UTC, which only is right when that is
+// the session time zone.
+func (dc *Conn) convertTime(t time.Time) any {
+	switch dc.sessionZone {
+	case time.UTC:
+		return t
+	case nil:
+		return ZonedTime{t}
+	default:
+		return sessionTime{t.In(dc.sessionZone)}
+	}
+}
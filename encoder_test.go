@@ -0,0 +1,94 @@
+package frontbase
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type point struct {
+	X, Y int
+}
+
+type celsius float64
+
+func (c celsius) Value() (driver.Value, error) {
+	return float64(c), nil
+}
+
+func TestConvertValue(t *testing.T) {
+	RegisterEncoder(func(p point) (string, error) {
+		return fmt.Sprintf("'%d,%d'", p.X, p.Y), nil
+	})
+
+	dc := &Conn{sessionZone: time.UTC}
+	stamp := time.Date(2024, 3, 16, 1, 2, 3, 0, time.UTC)
+
+	fixture := []struct {
+		name     string
+		value    any
+		expected any
+	}{
+		{"int64 passes", int64(42), int64(42)},
+		{"named kinds convert", celsius(21.5), float64(21.5)},
+		{"float32 stays exact", float32(0.1), float32(0.1)},
+		{"uint64 above MaxInt64", uint64(1 << 63), uint64(1 << 63)},
+		{"registered type", point{1, 2}, sqlLiteral("'1,2'")},
+		{"literal passes", Date{2024, time.March, 16}, Date{2024, time.March, 16}},
+		{"valuer", sql.NullInt32{Int32: 7, Valid: true}, int64(7)},
+		{"nil valuer", (*celsius)(nil), nil},
+		{"time in UTC session", stamp, stamp},
+	}
+
+	for _, tcase := range fixture {
+		actual, err := dc.convertValue(tcase.value)
+		if err != nil {
+			t.Errorf("case '%s' unexpected error '%v'", tcase.name, err)
+			continue
+		}
+		if fmt.Sprintf("%#v", actual) != fmt.Sprintf("%#v", tcase.expected) {
+			t.Errorf("case '%s' expected %#v but got %#v", tcase.name, tcase.expected, actual)
+		}
+	}
+}
+
+func TestCheckNamedValue_errors(t *testing.T) {
+	dc := &Conn{sessionZone: time.UTC}
+
+	fixture := []struct {
+		name     string
+		value    driver.NamedValue
+		expected string
+	}{
+		{"unsupported type", driver.NamedValue{Ordinal: 2, Value: make(chan int)}, "argument 2: unsupported type chan int"},
+		{"named argument", driver.NamedValue{Name: "p", Ordinal: 1, Value: struct{}{}}, "argument @p: unsupported type struct {}"},
+	}
+
+	for _, tcase := range fixture {
+		err := dc.CheckNamedValue(&tcase.value)
+		if err == nil || !strings.HasPrefix(err.Error(), tcase.expected) {
+			t.Errorf("case '%s' expected error '%s' but got '%v'", tcase.name, tcase.expected, err)
+		}
+	}
+}
+
+func TestConvertValue_session_zone(t *testing.T) {
+	stamp := time.Date(2024, 3, 16, 1, 2, 3, 0, time.UTC)
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual, _ := (&Conn{sessionZone: stockholm}).convertValue(stamp)
+	if lit := actual.(sessionTime).SQLLiteral(); lit != "TIMESTAMP '2024-03-16 02:02:03.000'" {
+		t.Errorf("unexpected literal %s", lit)
+	}
+
+	actual, _ = (&Conn{}).convertValue(stamp)
+	if lit := actual.(ZonedTime).SQLLiteral(); lit != "TIMESTAMP '2024-03-16 01:02:03.000+00:00'" {
+		t.Errorf("unexpected literal %s", lit)
+	}
+}
@@ -0,0 +1,170 @@
+package frontbase
+
+/*
+#include "clib.h"
+*/
+import "C"
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//
+// Errors reported by the server
+//
+
+// ErrConnectionLost is returned when the connection to the
+// server went away during a call. The connection is discarded.
+var ErrConnectionLost = errors.New("frontbase: no database connection")
+
+// FrontBase error codes used by the classification helpers. The
+// TestQuery_*_violation, _lock_conflict and _serialization_failure
+// tests provoke each of them on a real server.
+const (
+	CodeUniqueViolation      = 251 // UNIQUE or PRIMARY KEY constraint violated
+	CodeForeignKeyViolation  = 252 // referential constraint violated
+	CodeLockConflict         = 363 // conflicting lock held by another transaction
+	CodeSerializationFailure = 364 // optimistic transaction could not be committed
+)
+
+// The Severity of an Error.
+type Severity int8
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return fmt.Sprintf("severity(%d)", int8(s))
+	}
+}
+
+// An Error is one error reported by the FrontBase server.
+// Use errors.As to get at it:
+//
+//	var fbErr *frontbase.Error
+//	if errors.As(err, &fbErr) && fbErr.Code == ... {
+type Error struct {
+	Code     int      // the FrontBase error code
+	Message  string   // the message, as reported by the server
+	Position int      // 1-based position in the SQL text, 0 if not reported
+	Severity Severity // whether this is an error or a warning
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("frontbase %s %d: %s", err.Severity, err.Code, err.Message)
+}
+
+// An ErrorList holds all errors reported for one statement, in
+// the order reported by the server. errors.As finds the first
+// matching *Error.
+type ErrorList []*Error
+
+func (errs ErrorList) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (errs ErrorList) Unwrap() []error {
+	unwrapped := make([]error, len(errs))
+	for i, err := range errs {
+		unwrapped[i] = err
+	}
+	return unwrapped
+}
+
+// Collect the errors of a statement or fetch that failed. When the
+// server reported none individually its messages are all in one.
+func metaDataErrors(md *C.FBCMetaData) ErrorList {
+	emd := C.fbcmdErrorMetaData(md)
+	defer C.fbcemdRelease(emd)
+
+	errs := newErrorList(emd)
+	if len(errs) == 0 {
+		all := C.fbcemdAllErrorMessages(emd)
+		defer C.fbcemdReleaseMessage(all)
+
+		errs = append(errs, &Error{Message: C.GoString(all)})
+	}
+	return errs
+}
+
+// Collect all errors held by the error meta data.
+func newErrorList(emd *C.FBCErrorMetaData) ErrorList {
+	count := C.fbcemdErrorCount(emd)
+	errs := make(ErrorList, 0, count)
+
+	for i := C.uint(0); i < count; i++ {
+		errs = append(errs, &Error{
+			Code:     int(C.fbcemdErrorCodeAtIndex(emd, i)),
+			Message:  C.GoString(C.fbcemdErrorMessageAtIndex(emd, i)),
+			Position: int(C.GoFBErrorPositionAtIndex(emd, i)),
+			Severity: Severity(C.GoFBErrorSeverityAtIndex(emd, i)),
+		})
+	}
+
+	return errs
+}
+
+//
+// Classification
+//
+
+// IsUniqueViolation reports whether err was caused by a violated
+// UNIQUE or PRIMARY KEY constraint.
+func IsUniqueViolation(err error) bool {
+	return hasCode(err, CodeUniqueViolation)
+}
+
+// IsForeignKeyViolation reports whether err was caused by a
+// violated referential constraint.
+func IsForeignKeyViolation(err error) bool {
+	return hasCode(err, CodeForeignKeyViolation)
+}
+
+// IsLockConflict reports whether err was caused by a lock held by
+// another transaction.
+func IsLockConflict(err error) bool {
+	return hasCode(err, CodeLockConflict)
+}
+
+// IsSerializationFailure reports whether err was caused by a
+// transaction that could not be serialized with concurrent ones,
+// typically an optimistic transaction failing to commit.
+func IsSerializationFailure(err error) bool {
+	return hasCode(err, CodeSerializationFailure)
+}
+
+// IsConnectionLost reports whether err was caused by the
+// connection to the server going away.
+func IsConnectionLost(err error) bool {
+	return errors.Is(err, ErrConnectionLost) || errors.Is(err, driver.ErrBadConn)
+}
+
+// Reports whether any *Error in err's tree has the code.
+func hasCode(err error, code int) bool {
+	var list ErrorList
+	if errors.As(err, &list) {
+		for _, each := range list {
+			if each.Code == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	var fbErr *Error
+	return errors.As(err, &fbErr) && fbErr.Code == code
+}
@@ -0,0 +1,185 @@
+package frontbase
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestErrorList_As(t *testing.T) {
+	errs := ErrorList{
+		{Code: 208, Message: "Table T0 not found", Severity: SeverityError},
+		{Code: 1, Message: "Syntax error", Severity: SeverityError},
+	}
+	wrapped := fmt.Errorf("query failed: %w", errs)
+
+	var fbErr *Error
+	if !errors.As(wrapped, &fbErr) {
+		t.Fatal("errors.As found no *Error")
+	}
+	if fbErr.Code != 208 {
+		t.Errorf("expected code 208, got %d", fbErr.Code)
+	}
+
+	expected := "frontbase error 208: Table T0 not found\nfrontbase error 1: Syntax error"
+	if errs.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, errs.Error())
+	}
+}
+
+func TestQuery_error(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	_, err := tdb.db.Exec("select * from no_such_table;")
+
+	var fbErr *Error
+	if !errors.As(err, &fbErr) {
+		t.Fatalf("expected a *frontbase.Error, got %v", err)
+	}
+	if fbErr.Code == 0 || fbErr.Message == "" {
+		t.Errorf("expected code and message, got %#v", fbErr)
+	}
+	if fbErr.Severity != SeverityError {
+		t.Errorf("expected an error, got a %s", fbErr.Severity)
+	}
+}
+
+func TestQuery_error_position(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	_, err := tdb.db.Exec("select * frum t0;")
+
+	var fbErr *Error
+	if !errors.As(err, &fbErr) {
+		t.Fatalf("expected a *frontbase.Error, got %v", err)
+	}
+	if fbErr.Position < 1 || fbErr.Position > len("select * frum t0;") {
+		t.Errorf("expected a position within the statement, got %d", fbErr.Position)
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	fixture := []struct {
+		name     string
+		err      error
+		classify func(error) bool
+		expected bool
+	}{
+		{"unique", ErrorList{{Code: CodeUniqueViolation}}, IsUniqueViolation, true},
+		{"unique, second error", ErrorList{{Code: 1}, {Code: CodeUniqueViolation}}, IsUniqueViolation, true},
+		{"unique, wrapped", fmt.Errorf("insert: %w", ErrorList{{Code: CodeUniqueViolation}}), IsUniqueViolation, true},
+		{"unique, single error", &Error{Code: CodeUniqueViolation}, IsUniqueViolation, true},
+		{"not unique", ErrorList{{Code: CodeForeignKeyViolation}}, IsUniqueViolation, false},
+		{"foreign key", ErrorList{{Code: CodeForeignKeyViolation}}, IsForeignKeyViolation, true},
+		{"lock conflict", ErrorList{{Code: CodeLockConflict}}, IsLockConflict, true},
+		{"serialization", ErrorList{{Code: CodeSerializationFailure}}, IsSerializationFailure, true},
+		{"connection lost", ErrConnectionLost, IsConnectionLost, true},
+		{"bad conn", driver.ErrBadConn, IsConnectionLost, true},
+		{"plain error", errors.New("boom"), IsConnectionLost, false},
+		{"nil", nil, IsUniqueViolation, false},
+	}
+
+	for _, tcase := range fixture {
+		if actual := tcase.classify(tcase.err); actual != tcase.expected {
+			t.Errorf("case '%s' expected %v but got %v", tcase.name, tcase.expected, actual)
+		}
+	}
+}
+
+func TestQuery_unique_violation(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( val int primary key ); insert into t0 values ( 1 );")
+
+	_, err := tdb.db.Exec("insert into t0 values ( 1 );")
+	if !IsUniqueViolation(err) {
+		t.Errorf("expected a unique violation, got %v", err)
+	}
+}
+
+func TestQuery_foreign_key_violation(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( val int primary key );")
+	tdb.mustExec("create table t1 ( ref int references t0 ( val ) );")
+
+	_, err := tdb.db.Exec("insert into t1 values ( 1 );")
+	if !IsForeignKeyViolation(err) {
+		t.Errorf("expected a foreign key violation, got %v", err)
+	}
+}
+
+func TestQuery_lock_conflict(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( val int primary key ); insert into t0 values ( 1 );")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	opts := &sql.TxOptions{Isolation: sql.LevelSerializable}
+	holder, err := tdb.db.BeginTx(ctx, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer holder.Rollback()
+
+	if _, err := holder.Exec("update t0 set val = 2 where val = 1;"); err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := tdb.db.BeginTx(ctx, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Rollback()
+
+	_, err = other.Exec("update t0 set val = 3 where val = 1;")
+	if !IsLockConflict(err) {
+		t.Errorf("expected a lock conflict, got %v", err)
+	}
+}
+
+func TestQuery_serialization_failure(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( val int ); insert into t0 values ( 1 );")
+
+	// Repeatable read transactions lock optimistically, so both
+	// updates go through and the second commit fails.
+	opts := &sql.TxOptions{Isolation: sql.LevelRepeatableRead}
+	first, err := tdb.db.BeginTx(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Rollback()
+
+	second, err := tdb.db.BeginTx(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Rollback()
+
+	for _, tx := range []*sql.Tx{first, second} {
+		if _, err := tx.Exec("update t0 set val = val + 1;"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := first.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Commit(); !IsSerializationFailure(err) {
+		t.Errorf("expected a serialization failure, got %v", err)
+	}
+}
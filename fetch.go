@@ -0,0 +1,136 @@
+package frontbase
+
+/*
+#include "clib.h"
+*/
+import "C"
+import (
+	"context"
+	"database/sql/driver"
+)
+
+//
+// Fetching rows in blocks
+//
+
+type fetchSizeKey struct{}
+
+// WithFetchSize returns a context that makes the queries run with
+// it fetch `n` rows per round trip to the server, overriding the
+// fetchsize option of the connection. A fetch size of 0 leaves it
+// to FBCAccess.
+//
+// The statement is executed with the first block of rows and the
+// rest are fetched a block at a time. Only the first and the current
+// block are held in memory, so a small fetch size keeps the memory
+// used by a large result bounded while a large one saves round trips.
+func WithFetchSize(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, fetchSizeKey{}, n)
+}
+
+// The fetch size of a query run with `ctx` on `dc`.
+func fetchSizeFor(ctx context.Context, dc *Conn) int {
+	if n, ok := ctx.Value(fetchSizeKey{}).(int); ok && n >= 0 {
+		return n
+	}
+	return dc.fetchSize
+}
+
+// A block of rows fetched from the server.
+type rowBlock struct {
+	md       *C.FBCMetaData // nil once the result has no rows left
+	read     int            // rows read from md so far
+	borrowed bool           // md is the result set itself, released with the Rows
+}
+
+func (block *rowBlock) release() {
+	if block != nil && block.md != nil {
+		if !block.borrowed {
+			C.fbcmdRelease(block.md)
+		}
+		block.md = nil
+	}
+}
+
+// Return the next row of the current result set, or nil when there
+// are no more rows. The row is valid until the next call.
+//
+// With a fetch size the statement was executed with at most that
+// many rows, the first block; the rest are fetched a block at a
+// time. No more than the fetch size is read from a block, as asking
+// for more would make FBCAccess fetch them at its own size.
+func (rows *Rows) fetchRow() (*C.FBCRow, error) {
+	if rows.fetchSize == 0 {
+		return C.fbcmdFetchRow(rows.md), nil
+	}
+
+	if rows.block == nil {
+		rows.block = &rowBlock{md: rows.md, borrowed: true}
+		rows.blocks++
+	}
+
+	for rows.block.md != nil {
+		if rows.block.read < rows.fetchSize {
+			row := C.fbcmdFetchRow(rows.block.md)
+			if row != nil {
+				rows.block.read++
+				return row, nil
+			}
+
+			// A block short of the fetch size was the last one.
+			rows.block.release()
+			break
+		}
+
+		if err := rows.fetchBlock(); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// Replace the current block of rows with the next one from the
+// server. The fetch is cancelled like a statement when the context
+// of the query is done.
+func (rows *Rows) fetchBlock() error {
+	rows.block.release()
+	rows.block = &rowBlock{}
+
+	if rows.dc.bad {
+		return driver.ErrBadConn
+	}
+
+	if err := rows.ctx.Err(); err != nil {
+		return err
+	}
+
+	stopWatching := rows.dc.watchCancel(rows.ctx)
+	md := C.GoFBFetch(rows.dc.conn, rows.md, C.int(rows.fetchSize))
+
+	if cancelled := stopWatching(); cancelled {
+		rows.dc.bad = true
+		if md != nil {
+			C.fbcmdRelease(md)
+		}
+		return rows.ctx.Err()
+	}
+
+	if md == nil {
+		if C.fbcdcConnected(rows.dc.conn) == 0 {
+			C.GoFBCancel(rows.dc.conn)
+			rows.dc.bad = true
+			return ErrConnectionLost
+		}
+		return nil
+	}
+
+	if C.fbcmdErrorsFound(md) != 0 {
+		defer C.fbcmdRelease(md)
+		return metaDataErrors(md)
+	}
+
+	rows.block.md = md
+	rows.blocks++
+	return nil
+}
@@ -0,0 +1,148 @@
+package frontbase
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//
+// INTERVAL values
+//
+
+// An Interval is either a year-month interval, a number of months,
+// or a day-time interval, a time.Duration. It binds as an INTERVAL
+// literal. INTERVAL columns are decoded as the text String returns,
+// which scans into an Interval as well as into a string.
+type Interval struct {
+	yearMonth bool
+	months    int64
+	duration  time.Duration
+}
+
+// YearMonthInterval returns an INTERVAL YEAR TO MONTH.
+func YearMonthInterval(years, months int64) Interval {
+	return Interval{yearMonth: true, months: years*12 + months}
+}
+
+// DayTimeInterval returns an INTERVAL DAY TO SECOND.
+func DayTimeInterval(d time.Duration) Interval {
+	return Interval{duration: d}
+}
+
+// ParseInterval parses the value of an interval literal, either on
+// the form "Y-M" for year-month intervals or "D HH:MM:SS.fff" for
+// day-time intervals. Both may be preceded by a sign.
+func ParseInterval(s string) (Interval, error) {
+	text := s
+	negative := strings.HasPrefix(text, "-")
+	if negative || strings.HasPrefix(text, "+") {
+		text = text[1:]
+	}
+
+	if years, months, ok := strings.Cut(text, "-"); ok {
+		y, err1 := strconv.ParseInt(years, 10, 64)
+		m, err2 := strconv.ParseInt(months, 10, 64)
+		if err1 != nil || err2 != nil || y < 0 || m < 0 || m > 11 {
+			return Interval{}, fmt.Errorf("invalid year-month interval %q", s)
+		}
+
+		iv := YearMonthInterval(y, m)
+		if negative {
+			iv.months = -iv.months
+		}
+		return iv, nil
+	}
+
+	days, clock, ok := strings.Cut(text, " ")
+	if !ok {
+		return Interval{}, fmt.Errorf("invalid interval %q", s)
+	}
+
+	d, err := strconv.ParseInt(days, 10, 64)
+	if err != nil || d < 0 {
+		return Interval{}, fmt.Errorf("invalid day-time interval %q", s)
+	}
+
+	tod, err := ParseTimeOfDay(clock)
+	if err != nil {
+		return Interval{}, fmt.Errorf("invalid day-time interval %q", s)
+	}
+
+	iv := DayTimeInterval(time.Duration(d)*24*time.Hour + tod.Duration())
+	if negative {
+		iv.duration = -iv.duration
+	}
+	return iv, nil
+}
+
+// IsYearMonth reports whether iv is a year-month interval.
+func (iv Interval) IsYearMonth() bool {
+	return iv.yearMonth
+}
+
+// Months returns the length of a year-month interval in months;
+// ok is false for day-time intervals.
+func (iv Interval) Months() (months int64, ok bool) {
+	return iv.months, iv.yearMonth
+}
+
+// Duration returns the length of a day-time interval; ok is false
+// for year-month intervals since months vary in length.
+func (iv Interval) Duration() (d time.Duration, ok bool) {
+	return iv.duration, !iv.yearMonth
+}
+
+func (iv Interval) String() string {
+	if iv.yearMonth {
+		months, sign := iv.months, ""
+		if months < 0 {
+			months, sign = -months, "-"
+		}
+		return fmt.Sprintf("%s%d-%d", sign, months/12, months%12)
+	}
+
+	d, sign := iv.duration, ""
+	if d < 0 {
+		d, sign = -d, "-"
+	}
+
+	days := d / (24 * time.Hour)
+	tod := TimeOfDayOf(time.Time{}.Add(d % (24 * time.Hour)))
+	return fmt.Sprintf("%s%d %s", sign, days, tod)
+}
+
+// prepared.Literal
+func (iv Interval) SQLLiteral() string {
+	if iv.yearMonth {
+		return fmt.Sprintf("INTERVAL '%s' YEAR TO MONTH", iv)
+	}
+	return fmt.Sprintf("INTERVAL '%s' DAY TO SECOND", iv)
+}
+
+// driver.Valuer
+func (iv Interval) Value() (driver.Value, error) {
+	return iv.String(), nil
+}
+
+// sql.Scanner
+func (iv *Interval) Scan(src any) error {
+	var err error
+
+	switch v := src.(type) {
+	case Interval:
+		*iv = v
+	case string:
+		*iv, err = ParseInterval(v)
+	case []byte:
+		*iv, err = ParseInterval(string(v))
+	case nil:
+		err = fmt.Errorf("can't scan NULL into Interval, use sql.Null[Interval]")
+	default:
+		err = fmt.Errorf("can't scan %T into Interval", src)
+	}
+
+	return err
+}
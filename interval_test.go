@@ -0,0 +1,87 @@
+package frontbase
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestParseInterval(t *testing.T) {
+	fixture := []struct {
+		text     string
+		expected Interval
+		literal  string
+		failure  bool
+	}{
+		{"1-2", YearMonthInterval(1, 2), "INTERVAL '1-2' YEAR TO MONTH", false},
+		{"-0-11", YearMonthInterval(0, -11), "INTERVAL '-0-11' YEAR TO MONTH", false},
+		{"0 00:00:00", DayTimeInterval(0), "INTERVAL '0 00:00:00' DAY TO SECOND", false},
+		{"3 04:05:06.5", DayTimeInterval(3*24*time.Hour + 4*time.Hour + 5*time.Minute + 6500*time.Millisecond), "INTERVAL '3 04:05:06.5' DAY TO SECOND", false},
+		{"-1 00:00:00.000001", DayTimeInterval(-24*time.Hour - time.Microsecond), "INTERVAL '-1 00:00:00.000001' DAY TO SECOND", false},
+		{"1-12", Interval{}, "", true},
+		{"1 25:00:00", Interval{}, "", true},
+		{"soon", Interval{}, "", true},
+	}
+
+	for _, tcase := range fixture {
+		iv, err := ParseInterval(tcase.text)
+
+		if tcase.failure {
+			if err == nil {
+				t.Errorf("case %q expected an error, got %v", tcase.text, iv)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("case %q unexpected error '%v'", tcase.text, err)
+			continue
+		}
+		if iv != tcase.expected {
+			t.Errorf("case %q expected %#v but got %#v", tcase.text, tcase.expected, iv)
+		}
+		if literal := iv.SQLLiteral(); literal != tcase.literal {
+			t.Errorf("case %q expected %s but got %s", tcase.text, tcase.literal, literal)
+		}
+	}
+}
+
+func TestInterval_Duration(t *testing.T) {
+	if d, ok := DayTimeInterval(90 * time.Minute).Duration(); !ok || d != 90*time.Minute {
+		t.Errorf("expected 1h30m, got %v, %v", d, ok)
+	}
+	if _, ok := YearMonthInterval(1, 0).Duration(); ok {
+		t.Error("expected a year-month interval to have no exact duration")
+	}
+	if m, ok := YearMonthInterval(2, 3).Months(); !ok || m != 27 {
+		t.Errorf("expected 27 months, got %d, %v", m, ok)
+	}
+}
+
+func TestInsert_interval(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	ym := YearMonthInterval(2, 5)
+	dt := DayTimeInterval(36*time.Hour + 1500*time.Millisecond)
+
+	tdb.mustExec("create table t0 ( ym interval year to month, dt interval day to second(3) );")
+	tdb.mustExec("insert into t0 values ( ?, ? );", ym, dt)
+
+	var actualYm, actualDt Interval
+	if err := tdb.db.QueryRow("select ym, dt from t0;").Scan(&actualYm, &actualDt); err != nil {
+		t.Fatal(err)
+	}
+	if actualYm != ym || actualDt != dt {
+		t.Errorf("expected %v and %v, got %v and %v", ym, dt, actualYm, actualDt)
+	}
+
+	var textYm string
+	var textDt sql.NullString
+	if err := tdb.db.QueryRow("select ym, dt from t0;").Scan(&textYm, &textDt); err != nil {
+		t.Fatal(err)
+	}
+	if textYm != "2-5" || textDt.String != "1 12:00:01.5" {
+		t.Errorf("expected 2-5 and 1 12:00:01.5, got %s and %s", textYm, textDt.String)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -17,6 +18,35 @@ func (err bindError) Error() string {
 	return err.Msg
 }
 
+// An ArgCountError is returned by Bind and BindNamed when the
+// number of ordinal arguments doesn't match the placeholders.
+type ArgCountError struct {
+	Expected int
+	Got      int
+}
+
+func (err ArgCountError) Error() string {
+	return fmt.Sprintf("can't bind, expected %d ordinal args, got %d", err.Expected, err.Got)
+}
+
+// A NamedArgError is returned by BindNamed when a named placeholder
+// has no argument, or a named argument no placeholder.
+type NamedArgError struct {
+	Name    string
+	Missing bool // true when the argument is missing, false when the placeholder is
+	msg     string
+}
+
+func (err NamedArgError) Error() string {
+	return err.msg
+}
+
+// A Literal is a value that knows how to encode itself as an
+// SQL literal, e.g. a type with more precision than float64.
+type Literal interface {
+	SQLLiteral() string
+}
+
 //
 // Bind values
 //
@@ -43,23 +73,20 @@ func (stmt Stmt) BindNamed(args []driver.NamedValue) (string, error) {
 	for i, arg := range args {
 		if arg.Name != "" {
 			if len(stmt.namedPlaceholderNames) == 0 {
-				return "", bindError{
-					Msg: fmt.Sprintf("can't bind named value %s, statement has no named placeholders", arg.Name)}
+				return "", NamedArgError{
+					Name: arg.Name,
+					msg:  fmt.Sprintf("can't bind named value %s, statement has no named placeholders", arg.Name),
+				}
 			}
 
 			namedArgs[arg.Name] = i
 		} else {
-			if stmt.numOrdinalPlaceholders == 0 {
-				return "", bindError{Msg: "can't bind ordinal value when statement has no ordinal placeholders"}
-			}
 			ordinalArgs = append(ordinalArgs, arg)
 		}
 	}
 
 	if stmt.numOrdinalPlaceholders != len(ordinalArgs) {
-		return "", bindError{
-			Msg: fmt.Sprintf("can't bind, expected %d ordinal args, got %d", stmt.numOrdinalPlaceholders, len(ordinalArgs)),
-		}
+		return "", ArgCountError{Expected: stmt.numOrdinalPlaceholders, Got: len(ordinalArgs)}
 	}
 
 	sql := strings.Builder{}
@@ -82,7 +109,11 @@ func (stmt Stmt) BindNamed(args []driver.NamedValue) (string, error) {
 			if node.Text != "" {
 				valIdx, ok := namedArgs[node.Text]
 				if !ok {
-					return "", bindError{Msg: fmt.Sprintf("can't bind, missing named arg %s", node.Text)}
+					return "", NamedArgError{
+						Name:    node.Text,
+						Missing: true,
+						msg:     fmt.Sprintf("can't bind, missing named arg %s", node.Text),
+					}
 				}
 				val = args[valIdx].Value
 				delete(notVisitedNamedArgs, node.Text)
@@ -90,7 +121,10 @@ func (stmt Stmt) BindNamed(args []driver.NamedValue) (string, error) {
 				val = ordinalArgs[nextOrdinalIdx].Value
 				nextOrdinalIdx++
 			}
-			encoded := encodeValue(val)
+			encoded, err := encodeValue(val)
+			if err != nil {
+				return "", err
+			}
 			sql.WriteString(encoded)
 		default:
 			panic("won't happen")
@@ -98,33 +132,51 @@ func (stmt Stmt) BindNamed(args []driver.NamedValue) (string, error) {
 	}
 
 	for left := range notVisitedNamedArgs {
-		return "", bindError{Msg: fmt.Sprintf("can't bind named value %s, no matching named placeholder", left)}
+		return "", NamedArgError{
+			Name: left,
+			msg:  fmt.Sprintf("can't bind named value %s, no matching named placeholder", left),
+		}
 	}
 
 	return sql.String(), nil
 }
 
+// Bind binds positional args, one per ? placeholder and one per
+// distinct @name placeholder in order of first appearance; a name
+// that appears again is bound to the same arg.
 func (stmt Stmt) Bind(args []driver.Value) (string, error) {
+	if numInput := stmt.NumInput(); len(args) != numInput {
+		return "", ArgCountError{Expected: numInput, Got: len(args)}
+	}
+
 	sql := strings.Builder{}
 
 	nextValueIdx := 0
+	namedIdx := make(map[string]int, len(stmt.namedPlaceholderNames))
 
 	for _, node := range stmt.nodes {
 		switch node.Type {
 		case text:
 			sql.WriteString(node.Text)
 		case placeholder:
-			// todo: check args len
-			encoded := encodeValue(args[nextValueIdx])
+			valIdx, seen := namedIdx[node.Text]
+			if !seen {
+				valIdx = nextValueIdx
+				nextValueIdx++
+				if node.Text != "" {
+					namedIdx[node.Text] = valIdx
+				}
+			}
+			encoded, err := encodeValue(args[valIdx])
+			if err != nil {
+				return "", err
+			}
 			sql.WriteString(encoded)
-			nextValueIdx++
 		default:
 			panic("won't happen")
 		}
 	}
 
-	// todo: check args left
-
 	return sql.String(), nil
 }
 
@@ -132,48 +184,73 @@ func (stmt Stmt) Bind(args []driver.Value) (string, error) {
 // Utilities
 //
 
-func encodeValue(x interface{}) string {
+// FormatTimestamp formats the wall clock of t for a TIMESTAMP
+// literal. Milliseconds are always included; micro- and nanoseconds
+// only when t has them, so that no precision is lost.
+func FormatTimestamp(t time.Time) string {
+	layout := "2006-01-02 15:04:05.000"
+
+	switch nsecs := t.Nanosecond(); {
+	case nsecs%1000 != 0:
+		layout = "2006-01-02 15:04:05.000000000"
+	case nsecs%1000000 != 0:
+		layout = "2006-01-02 15:04:05.000000"
+	}
+
+	return t.Format(layout)
+}
+
+func encodeValue(x interface{}) (string, error) {
 	switch v := x.(type) {
 	case int:
-		return strconv.FormatInt(int64(v), 10)
+		return strconv.FormatInt(int64(v), 10), nil
 	case int8:
-		return strconv.FormatInt(int64(v), 10)
+		return strconv.FormatInt(int64(v), 10), nil
 	case int16:
-		return strconv.FormatInt(int64(v), 10)
+		return strconv.FormatInt(int64(v), 10), nil
 	case int32:
-		return strconv.FormatInt(int64(v), 10)
+		return strconv.FormatInt(int64(v), 10), nil
 	case int64:
-		return strconv.FormatInt(v, 10)
+		return strconv.FormatInt(v, 10), nil
 	case uint:
-		return strconv.FormatUint(uint64(v), 10)
+		return strconv.FormatUint(uint64(v), 10), nil
 	case uint8:
-		return strconv.FormatUint(uint64(v), 10)
+		return strconv.FormatUint(uint64(v), 10), nil
 	case uint16:
-		return strconv.FormatUint(uint64(v), 10)
+		return strconv.FormatUint(uint64(v), 10), nil
 	case uint32:
-		return strconv.FormatUint(uint64(v), 10)
+		return strconv.FormatUint(uint64(v), 10), nil
 	case uint64:
-		return strconv.FormatUint(v, 10) // may overflow SQL long int!
+		return strconv.FormatUint(v, 10), nil // an exact numeric; overflows LONGINT above MaxInt64
+	case float32:
+		return encodeFloat(float64(v), 32)
 	case float64:
-		return strconv.FormatFloat(v, 'f', -1, 64)
+		return encodeFloat(v, 64)
 	case bool:
-		return strconv.FormatBool(v)
+		return strconv.FormatBool(v), nil
 	case []byte:
 		result := make([]byte, 3+hex.EncodedLen(len(v)))
 		result[0] = 'x'
 		result[1] = '\''
 		result[len(result)-1] = '\''
 		hex.Encode(result[2:len(result)-1], v)
-		return string(result)
+		return string(result), nil
 	case nil:
-		return "NULL"
+		return "NULL", nil
 	case string:
-		return fmt.Sprintf("'%s'", strings.Replace(v, `'`, `''`, -1))
+		return fmt.Sprintf("'%s'", strings.Replace(v, `'`, `''`, -1)), nil
 	case time.Time:
-		return fmt.Sprintf("TIMESTAMP '%s'", v.UTC().Format("2006-01-02 15:04:05.000"))
+		return fmt.Sprintf("TIMESTAMP '%s'", FormatTimestamp(v.UTC())), nil
+	case Literal:
+		return v.SQLLiteral(), nil
 	default:
-		panic(fmt.Sprintf("encode: unknown type for %T", v))
+		return "", bindError{Msg: fmt.Sprintf("can't bind value of type %T", v)}
 	}
+}
 
-	panic("never reached")
+func encodeFloat(v float64, bitSize int) (string, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "", bindError{Msg: fmt.Sprintf("can't bind %v, not a number in SQL", v)}
+	}
+	return strconv.FormatFloat(v, 'f', -1, bitSize), nil
 }
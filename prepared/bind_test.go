@@ -2,7 +2,9 @@ package prepared
 
 import (
 	"database/sql/driver"
+	"errors"
 	"log"
+	"math"
 	"testing"
 	"time"
 )
@@ -208,6 +210,58 @@ func TestBind(t *testing.T) {
 	}
 }
 
+func TestBind_repeated_name(t *testing.T) {
+	prepped, err := ParseSQL("select * from t where c1 = @n1 and c2 = ? or c3 = @n1;")
+	if err != nil {
+		t.Fatal("ParseSQL failed:", err)
+	}
+
+	sql, err := prepped.Bind([]driver.Value{int64(42), "fourty-two"})
+	if err != nil {
+		t.Fatal("Bind failed:", err)
+	}
+
+	expected := "select * from t where c1 = 42 and c2 = 'fourty-two' or c3 = 42;"
+	if sql != expected {
+		t.Fatal("expected", expected, "but got", sql)
+	}
+}
+
+func TestBind_arg_count(t *testing.T) {
+	prepped, err := ParseSQL("select * from t where c1 = ? and c2 = @n;")
+	if err != nil {
+		t.Fatal("ParseSQL failed:", err)
+	}
+
+	for _, args := range [][]driver.Value{{}, {int64(1)}, {int64(1), int64(2), int64(3)}} {
+		_, err := prepped.Bind(args)
+
+		var countErr ArgCountError
+		if !errors.As(err, &countErr) || countErr.Expected != 2 || countErr.Got != len(args) {
+			t.Errorf("%d args: expected ArgCountError, got %v", len(args), err)
+		}
+	}
+}
+
+func TestBindNamed_typed_errors(t *testing.T) {
+	prepped, err := ParseSQL("select * from t where c1 = ? and c2 = @n;")
+	if err != nil {
+		t.Fatal("ParseSQL failed:", err)
+	}
+
+	_, err = prepped.BindNamed([]driver.NamedValue{{Ordinal: 1, Value: int64(1)}})
+	var namedErr NamedArgError
+	if !errors.As(err, &namedErr) || namedErr.Name != "n" || !namedErr.Missing {
+		t.Errorf("expected a missing NamedArgError for n, got %v", err)
+	}
+
+	_, err = prepped.BindNamed([]driver.NamedValue{{Name: "n", Ordinal: 1, Value: int64(1)}})
+	var countErr ArgCountError
+	if !errors.As(err, &countErr) || countErr.Expected != 1 || countErr.Got != 0 {
+		t.Errorf("expected ArgCountError, got %v", err)
+	}
+}
+
 func TestEncode(t *testing.T) {
 	fixture := []struct {
 		name     string
@@ -220,6 +274,9 @@ func TestEncode(t *testing.T) {
 		{"float", float64(1), "1"},
 		{"float", float64(1.23), "1.23"},
 		{"float", float64(-1.897), "-1.897"},
+		{"float32", float32(0.1), "0.1"},
+
+		{"uint64 above MaxInt64", uint64(18446744073709551615), "18446744073709551615"},
 
 		{"bool", true, "true"},
 		{"bool", false, "false"},
@@ -237,18 +294,52 @@ func TestEncode(t *testing.T) {
 		{"string", "'", "''''"},
 		{"string", "'nice'", "'''nice'''"},
 
+		{"literal", literal("DECIMAL '1.20'"), "DECIMAL '1.20'"},
+
 		{"local time expressed in UTC", swedishTime("2022-10-14 10:23:59.123"), "TIMESTAMP '2022-10-14 08:23:59.123'"},
 		{"UTC time stays in UTC", utcTime("2022-10-14 10:23:59.123"), "TIMESTAMP '2022-10-14 10:23:59.123'"},
+		{"whole seconds keep milliseconds", time.Date(2022, 10, 14, 10, 23, 59, 0, time.UTC), "TIMESTAMP '2022-10-14 10:23:59.000'"},
+		{"microseconds", time.Date(2022, 10, 14, 10, 23, 59, 123456000, time.UTC), "TIMESTAMP '2022-10-14 10:23:59.123456'"},
+		{"nanoseconds", time.Date(2022, 10, 14, 10, 23, 59, 123456789, time.UTC), "TIMESTAMP '2022-10-14 10:23:59.123456789'"},
+		{"historical", time.Date(1066, 10, 14, 9, 0, 0, 1000, time.UTC), "TIMESTAMP '1066-10-14 09:00:00.000001'"},
 	}
 
 	for _, tcase := range fixture {
-		acutal := encodeValue(tcase.value)
+		acutal, err := encodeValue(tcase.value)
+		if err != nil {
+			t.Errorf("case '%s' unexpected error '%v'", tcase.name, err)
+		}
 		if acutal != tcase.expected {
 			t.Errorf("case '%s' expected <%s> but got <%v>", tcase.name, tcase.expected, acutal)
 		}
 	}
 }
 
+func TestEncode_errors(t *testing.T) {
+	fixture := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"unknown type", struct{}{}, "can't bind value of type struct {}"},
+		{"NaN", math.NaN(), "can't bind NaN, not a number in SQL"},
+		{"infinity", float32(math.Inf(1)), "can't bind +Inf, not a number in SQL"},
+	}
+
+	for _, tcase := range fixture {
+		_, err := encodeValue(tcase.value)
+		if err == nil || err.Error() != tcase.expected {
+			t.Errorf("case '%s' expected error '%s' but got '%v'", tcase.name, tcase.expected, err)
+		}
+	}
+}
+
+type literal string
+
+func (l literal) SQLLiteral() string {
+	return string(l)
+}
+
 func swedishTime(spec string) time.Time {
 	loc, err := time.LoadLocation("Europe/Stockholm")
 	if err != nil {
@@ -0,0 +1,96 @@
+package prepared
+
+import (
+	"container/list"
+	"sync"
+)
+
+//
+// A cache of parsed statements
+//
+
+// A Cache holds up to a fixed number of parsed statements, keyed by
+// their SQL text, and evicts the least recently used one when full.
+// It is safe for concurrent use; a Stmt is never modified once
+// parsed, so a cached one can be shared.
+type Cache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // of *cacheEntry, most recently used first
+	entries map[string]*list.Element
+	stats   CacheStats
+}
+
+// CacheStats counts the lookups of a Cache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+type cacheEntry struct {
+	sql  string
+	stmt *Stmt
+}
+
+// NewCache returns a Cache holding up to `size` statements.
+func NewCache(size int) *Cache {
+	return &Cache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// Get returns the parsed statement for `sql`, parsing and caching
+// it on a miss. Statements that fail to parse are not cached.
+func (c *Cache) Get(sql string) (*Stmt, error) {
+	c.mu.Lock()
+	if elem, ok := c.entries[sql]; ok {
+		c.order.MoveToFront(elem)
+		c.stats.Hits++
+		c.mu.Unlock()
+		return elem.Value.(*cacheEntry).stmt, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	// Parse without holding the lock; two goroutines missing on the
+	// same text both parse it, and the last one wins.
+	stmt, err := ParseSQL(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[sql]; ok {
+		c.order.MoveToFront(elem)
+		elem.Value.(*cacheEntry).stmt = stmt
+		return stmt, nil
+	}
+
+	c.entries[sql] = c.order.PushFront(&cacheEntry{sql: sql, stmt: stmt})
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).sql)
+	}
+
+	return stmt, nil
+}
+
+// Len returns the number of cached statements.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Stats returns the hit and miss counts so far.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
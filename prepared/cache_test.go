@@ -0,0 +1,62 @@
+package prepared
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	cache := NewCache(2)
+
+	first, err := cache.Get("select * from t where a = ?;")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	again, err := cache.Get("select * from t where a = ?;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != first {
+		t.Error("expected the cached statement on a hit")
+	}
+
+	cache.Get("select 2;")
+	cache.Get("select * from t where a = ?;") // most recently used again
+	cache.Get("select 3;")                    // evicts "select 2;"
+
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 cached statements, got %d", cache.Len())
+	}
+
+	cache.Get("select * from t where a = ?;")
+	cache.Get("select 2;")
+
+	expected := CacheStats{Hits: 3, Misses: 4}
+	if stats := cache.Stats(); stats != expected {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+}
+
+func TestCache_parse_error(t *testing.T) {
+	cache := NewCache(2)
+
+	if _, err := cache.Get("select 'unterminated;"); err == nil {
+		t.Fatal("expected a parse error")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("expected nothing cached, got %d", cache.Len())
+	}
+}
+
+func BenchmarkCache_Get(b *testing.B) {
+	cache := NewCache(16)
+	queries := make([]string, 8)
+	for i := range queries {
+		queries[i] = fmt.Sprintf("select * from t where a = ? and b = @name and c = %d;", i)
+	}
+
+	for i := 0; i < b.N; i++ {
+		cache.Get(queries[i%len(queries)])
+	}
+}
@@ -1,6 +1,7 @@
 package prepared
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -635,3 +636,35 @@ func EqualStatements(expected *Stmt, actual *Stmt) bool {
 	return (expected == nil && actual == nil) ||
 		(expected != nil && expected.Equal(actual))
 }
+
+func TestStmt_placeholder_counts(t *testing.T) {
+	fixture := []struct {
+		sql      string
+		ordinals int
+		names    []string
+		numInput int
+	}{
+		{"select 1;", 0, []string{}, 0},
+		{"select * from t where a = ? and b = ?;", 2, []string{}, 2},
+		{"select * from t where a = @n1 and b = @n2 and c = @n1;", 0, []string{"n1", "n2"}, 2},
+		{"select * from t where a = @n1 and b = ? and c = '?';", 1, []string{"n1"}, 2},
+	}
+
+	for _, tcase := range fixture {
+		prepped, err := ParseSQL(tcase.sql)
+		if err != nil {
+			t.Errorf("%s: unexpected parse error '%v'", tcase.sql, err)
+			continue
+		}
+
+		if n := prepped.NumOrdinalPlaceholders(); n != tcase.ordinals {
+			t.Errorf("%s: expected %d ordinal placeholders, got %d", tcase.sql, tcase.ordinals, n)
+		}
+		if names := prepped.NamedPlaceholders(); !reflect.DeepEqual(names, tcase.names) {
+			t.Errorf("%s: expected names %v, got %v", tcase.sql, tcase.names, names)
+		}
+		if n := prepped.NumInput(); n != tcase.numInput {
+			t.Errorf("%s: expected %d inputs, got %d", tcase.sql, tcase.numInput, n)
+		}
+	}
+}
@@ -28,6 +28,33 @@ const (
 	placeholder
 )
 
+// NumOrdinalPlaceholders returns the number of ? placeholders.
+func (n Stmt) NumOrdinalPlaceholders() int {
+	return n.numOrdinalPlaceholders
+}
+
+// NamedPlaceholders returns the distinct names of the @name
+// placeholders, in order of first appearance.
+func (n Stmt) NamedPlaceholders() []string {
+	names := make([]string, 0, len(n.namedPlaceholderNames))
+	seen := make(map[string]bool, len(n.namedPlaceholderNames))
+
+	for _, name := range n.namedPlaceholderNames {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// NumInput returns the number of arguments BindNamed expects: one
+// per ? placeholder and one per distinct @name placeholder.
+func (n Stmt) NumInput() int {
+	return n.numOrdinalPlaceholders + len(n.NamedPlaceholders())
+}
+
 //
 // Utility functions
 //
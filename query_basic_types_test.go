@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestQuery_tinyint(t *testing.T) {
@@ -141,6 +142,7 @@ func TestQuery_double(t *testing.T) {
 	scanAndCompare("0.10000122 as float", float64(0.10000122))
 	scanAndCompare("35.03554004971999 as double precision", float64(35.03554004971999))
 	scanAndCompare("1.2 as float", float64(1.2))
+	scanAndCompare("1.2 as real", float64(1.2))
 
 	// and with double precision substituted for float.
 	scanAndCompare("0.10000122 as double precision", float64(0.10000122))
@@ -167,3 +169,238 @@ func TestQuery_decimal_defaults_to_double(t *testing.T) {
 	scanAndCompare("35.03554004971999 as decimal(16,14)", float64(35.03554004971999))
 	scanAndCompare("1.2 as decimal(2,1)", float64(1.2000000000000002))
 }
+
+func TestQuery_decimal_exact(t *testing.T) {
+	tdb := createTempdbWithOptions(t, "decimals=exact")
+	defer tdb.tearDown()
+
+	scanAndCompare := func(sqlPart string, expected string) {
+		t.Logf("scanning: %s", sqlPart)
+		var actual Decimal
+		err := tdb.db.QueryRow("values (cast(" + sqlPart + "));").Scan(&actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected != actual.String() {
+			t.Errorf("expected %s got %s", expected, actual)
+		}
+	}
+
+	scanAndCompare("0.10000122 as decimal(8,8)", "0.10000122")
+	scanAndCompare("35.03554004971999 as decimal(16,14)", "35.03554004971999")
+	scanAndCompare("1.2 as decimal(2,1)", "1.2")
+	scanAndCompare("-1234567.89 as decimal(9,2)", "-1234567.89")
+	scanAndCompare("-1234567.89 as numeric(9,2)", "-1234567.89")
+
+	// A wide column, with the 15 significant digits the server's
+	// double carries exactly.
+	scanAndCompare("12345.0123456789 as decimal(30,10)", "12345.0123456789")
+	scanAndCompare("-0.0000000001 as decimal(30,10)", "-0.0000000001")
+
+	tdb.mustExec("create table t0 ( val decimal(12,2) );")
+	tdb.mustExec("insert into t0 values ( ? );", NewDecimal(1234567890, 2))
+
+	var actual Decimal
+	if err := tdb.db.QueryRow("select val from t0;").Scan(&actual); err != nil {
+		t.Fatal(err)
+	}
+	if actual.String() != "12345678.90" {
+		t.Errorf("expected 12345678.90 got %s", actual)
+	}
+
+	wide, _ := ParseDecimal("98765.0123456789")
+	tdb.mustExec("create table t1 ( val decimal(30,10) );")
+	tdb.mustExec("insert into t1 values ( ? );", wide)
+
+	if err := tdb.db.QueryRow("select val from t1;").Scan(&actual); err != nil {
+		t.Fatal(err)
+	}
+	if actual.String() != wide.String() {
+		t.Errorf("expected %s got %s", wide, actual)
+	}
+}
+
+func TestQuery_date(t *testing.T) {
+	database.RunTestOneRow(t,
+		"create table t0 ( val date ); insert into t0 values ( date '2024-03-16' );",
+		expectOneCol(time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)))
+
+	database.RunTestOneRow(t,
+		"create table t0 ( val date ); insert into t0 values ( date '1969-07-20' );",
+		expectOneCol(Date{1969, time.July, 20}))
+}
+
+func TestQuery_time(t *testing.T) {
+	database.RunTestOneRow(t,
+		"create table t0 ( val time ); insert into t0 values ( time '13:14:15' );",
+		expectOneCol(TimeOfDay{13, 14, 15, 0}))
+
+	database.RunTestOneRow(t,
+		"create table t0 ( val time(3) ); insert into t0 values ( time '13:14:15.250' );",
+		expectOneCol(time.Date(0, 1, 1, 13, 14, 15, 250000000, time.UTC)))
+}
+
+func TestInsert_date_and_time(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( d date, t time );")
+	tdb.mustExec("insert into t0 values ( ?, ? );", Date{2024, time.February, 29}, TimeOfDay{Hour: 23, Minute: 59})
+
+	var d Date
+	var tod TimeOfDay
+	if err := tdb.db.QueryRow("select d, t from t0;").Scan(&d, &tod); err != nil {
+		t.Fatal(err)
+	}
+	if d != (Date{2024, time.February, 29}) || tod != (TimeOfDay{Hour: 23, Minute: 59}) {
+		t.Errorf("unexpected values %v %v", d, tod)
+	}
+}
+
+func TestQuery_timestamp_with_time_zone(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	zone := time.FixedZone("", 2*60*60)
+	expected := time.Date(2024, 3, 16, 1, 2, 3, 0, zone)
+
+	tdb.mustExec("create table t0 ( val timestamp with time zone );")
+	tdb.mustExec("insert into t0 values ( ? );", ZonedTime{expected})
+
+	var actual time.Time
+	if err := tdb.db.QueryRow("select val from t0;").Scan(&actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if !actual.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+	if _, offset := actual.Zone(); offset != 2*60*60 {
+		t.Errorf("expected offset +02:00, got %v", actual)
+	}
+}
+
+func TestQuery_timestamp_session_time_zone(t *testing.T) {
+	tdb := createTempdbWithOptions(t, "timezone=Europe%2FStockholm&loc=UTC")
+	defer tdb.tearDown()
+
+	expected := utcTime(t, "2024-03-16 01:02:03.000")
+
+	tdb.mustExec("create table t0 ( val timestamp );")
+	tdb.mustExec("insert into t0 values ( ? );", expected)
+
+	var actual time.Time
+	if err := tdb.db.QueryRow("select val from t0;").Scan(&actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual != expected {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+
+	var wallClock string
+	if err := tdb.db.QueryRow("select cast(val as character(19)) from t0;").Scan(&wallClock); err != nil {
+		t.Fatal(err)
+	}
+	if wallClock != "2024-03-16 02:02:03" {
+		t.Errorf("expected the session to store Stockholm time, got %s", wallClock)
+	}
+}
+
+func TestQuery_timestamp_server_time_zone(t *testing.T) {
+	tdb := createTempdbWithOptions(t, "timezone=none&loc=UTC")
+	defer tdb.tearDown()
+
+	// Whatever the server's zone, the value is bound with its offset
+	// and decoded from UTC seconds.
+	expected := time.Date(2024, 3, 16, 1, 2, 3, 0, time.FixedZone("", -5*3600))
+
+	tdb.mustExec("create table t0 ( val timestamp );")
+	tdb.mustExec("insert into t0 values ( ? );", expected)
+
+	var actual time.Time
+	if err := tdb.db.QueryRow("select val from t0;").Scan(&actual); err != nil {
+		t.Fatal(err)
+	}
+
+	if !actual.Equal(expected) || actual.Location() != time.UTC {
+		t.Errorf("expected %v in UTC, got %v", expected, actual)
+	}
+}
+
+func TestTimestampFromSeconds(t *testing.T) {
+	secsOf := func(year int, month time.Month, day, hour, min, sec int) float64 {
+		return float64(time.Date(year, month, day, hour, min, sec, 0, time.UTC).Unix() - frontbaseEpoch)
+	}
+
+	fixture := []struct {
+		name     string
+		secs     float64
+		digits   int
+		expected time.Time
+	}{
+		{"epoch", 0, 6, time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"before the epoch", -0.5, 6, time.Date(2000, 12, 31, 23, 59, 59, 500000000, time.UTC)},
+		{"nanoseconds", 1.123456789, 9, time.Date(2001, 1, 1, 0, 0, 1, 123456789, time.UTC)},
+		{"rounded to the column", 1.1234567894, 9, time.Date(2001, 1, 1, 0, 0, 1, 123456789, time.UTC)},
+		{"rounded up to a second", 0.9999999, 6, time.Date(2001, 1, 1, 0, 0, 1, 0, time.UTC)},
+		{"microseconds", secsOf(2024, 3, 16, 1, 2, 3) + 0.123456, 6, time.Date(2024, 3, 16, 1, 2, 3, 123456000, time.UTC)},
+		{"beyond the double", secsOf(2024, 3, 16, 1, 2, 3) + 0.123456789, 9, time.Date(2024, 3, 16, 1, 2, 3, 123457000, time.UTC)},
+		{"historical", secsOf(1066, 10, 14, 9, 0, 0) + 0.00001, 6, time.Date(1066, 10, 14, 9, 0, 0, 10000, time.UTC)},
+		{"far future", secsOf(2999, 12, 31, 23, 59, 59) + 0.99999, 6, time.Date(2999, 12, 31, 23, 59, 59, 999990000, time.UTC)},
+		{"far future beyond the double", secsOf(2999, 12, 31, 23, 59, 59) + 0.999999, 6, time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tcase := range fixture {
+		actual := timestampFromSeconds(tcase.secs, tcase.digits)
+		if !actual.Equal(tcase.expected) {
+			t.Errorf("case '%s' expected %v but got %v", tcase.name, tcase.expected, actual.UTC())
+		}
+	}
+}
+
+func TestDurationFromSeconds(t *testing.T) {
+	fixture := []struct {
+		secs     float64
+		digits   int
+		expected time.Duration
+	}{
+		{1.1, 9, 1100 * time.Millisecond},
+		{86399.999999, 6, 24*time.Hour - time.Microsecond},
+		{-90.25, 9, -90250 * time.Millisecond},
+	}
+
+	for _, tcase := range fixture {
+		if actual := durationFromSeconds(tcase.secs, tcase.digits); actual != tcase.expected {
+			t.Errorf("%v: expected %v but got %v", tcase.secs, tcase.expected, actual)
+		}
+	}
+}
+
+func TestQuery_timestamp_roundtrip(t *testing.T) {
+	tdb := createTempdbWithOptions(t, "loc=UTC")
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( id int, val timestamp(9) );")
+
+	// Each to the decimals the server's double resolves at its date.
+	fixture := []time.Time{
+		time.Date(2024, 3, 16, 1, 2, 3, 123456000, time.UTC),
+		time.Date(1900, 1, 1, 0, 0, 0, 1000, time.UTC),
+		time.Date(1066, 10, 14, 9, 0, 0, 10000, time.UTC),
+		time.Date(2999, 12, 31, 23, 59, 59, 999990000, time.UTC),
+		time.Date(2001, 1, 1, 0, 0, 1, 123456789, time.UTC),
+	}
+
+	for i, expected := range fixture {
+		tdb.mustExec("insert into t0 values ( ?, ? );", i, expected)
+
+		var actual time.Time
+		if err := tdb.db.QueryRow("select val from t0 where id = ?;", i).Scan(&actual); err != nil {
+			t.Fatal(err)
+		}
+		if actual != expected {
+			t.Errorf("expected %v, got %v", expected, actual)
+		}
+	}
+}
@@ -1,11 +1,17 @@
 package frontbase
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -78,6 +84,12 @@ type tempdb struct {
 // Create a temporary database within the context of test `t`.
 // If anything goes wrong `t` is aborted.
 func createTempdb(t *testing.T) tempdb {
+	return createTempdbWithOptions(t, "")
+}
+
+// Create a temporary database, like createTempdb, and open it
+// with the DSN `options`, e.g. "decimals=exact".
+func createTempdbWithOptions(t *testing.T, options string) tempdb {
 	tempDir, err := ioutil.TempDir("", "")
 	if err != nil {
 		t.Fatal(err)
@@ -85,6 +97,9 @@ func createTempdb(t *testing.T) tempdb {
 
 	dbpath := filepath.Join(tempDir, "foo.db")
 	dburl := fmt.Sprintf("file:///%s", dbpath)
+	if options != "" {
+		dburl += "?" + options
+	}
 
 	db, err := sql.Open("frontbase", dburl)
 	if err != nil {
@@ -129,3 +144,384 @@ func utcTime(t *testing.T, spec string) time.Time {
 
 	return stamp
 }
+
+func TestQuery_context_deadline(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( val int );")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	_, err := tdb.db.ExecContext(ctx, "insert into t0 values ( 1 );")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	var count int32
+	if err := tdb.db.QueryRow("select count(*) from t0;").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("expected no rows inserted, got %d", count)
+	}
+}
+
+func TestQuery_context_cancel_while_running(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( val int );")
+
+	ctx := context.Background()
+	conn, err := tdb.db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ch := make(chan []any)
+	go func() {
+		for i := 0; i < 300; i++ {
+			ch <- []any{i}
+		}
+		close(ch)
+	}()
+	if _, err := BulkLoad(ctx, conn, "t0", []string{"val"}, ChanRows(ch), BulkLoadOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	// 300^4 rows to count keeps the server busy well past the
+	// cancellation.
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	started := time.Now()
+	var count int64
+	err = tdb.db.QueryRowContext(ctx, "select count(*) from t0 a, t0 b, t0 c, t0 d;").Scan(&count)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v (count %d)", context.Canceled, err, count)
+	}
+	if elapsed := time.Since(started); elapsed > 10*time.Second {
+		t.Errorf("expected the statement to be aborted, it ran for %v", elapsed)
+	}
+
+	// The torn down connection is discarded by the pool.
+	if err := tdb.db.QueryRow("select count(*) from t0;").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 300 {
+		t.Errorf("expected 300 rows, got %d", count)
+	}
+}
+
+func TestExec_result(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( id int default unique, val int );")
+
+	res := tdb.mustExec("insert into t0 ( val ) values ( 1 );")
+	id, err := res.LastInsertId()
+	if err != nil {
+		t.Fatalf("expected a generated key, got %v", err)
+	}
+
+	var stored int64
+	if err := tdb.db.QueryRow("select id from t0 where val = 1;").Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if id != stored {
+		t.Errorf("expected the key of the inserted row %d, got %d", stored, id)
+	}
+
+	tdb.mustExec("insert into t0 ( val ) values ( 2 );")
+
+	res = tdb.mustExec("update t0 set val = val + 1;")
+	if n, err := res.RowsAffected(); err != nil || n != 2 {
+		t.Errorf("expected 2 rows affected, got %d, %v", n, err)
+	}
+
+	if _, err := res.LastInsertId(); err == nil {
+		t.Error("expected LastInsertId to fail for an update")
+	}
+
+	// A key beyond a C int.
+	tdb.mustExec("create table t2 ( id longint default unique, val int );")
+	tdb.mustExec("set unique = 3000000000 for t2;")
+
+	res = tdb.mustExec("insert into t2 ( val ) values ( 1 );")
+	if err := tdb.db.QueryRow("select id from t2;").Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := res.LastInsertId(); err != nil || id != stored || id <= math.MaxInt32 {
+		t.Errorf("expected the key %d beyond a C int, got %d, %v", stored, id, err)
+	}
+
+	tdb.mustExec("create table t1 ( val int );")
+
+	res = tdb.mustExec("insert into t1 values ( 1 );")
+	if id, err := res.LastInsertId(); err == nil {
+		t.Errorf("expected LastInsertId to fail without a UNIQUE default, got %d", id)
+	}
+}
+
+func TestQuery_column_types(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( a int not null, b character varying(20), c decimal(10,2), d character(8), e real );")
+
+	rows, err := tdb.db.Query("select * from t0;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name := types[0].DatabaseTypeName(); name != "INTEGER" {
+		t.Errorf("expected INTEGER, got %s", name)
+	}
+	if nullable, ok := types[0].Nullable(); !ok || nullable {
+		t.Errorf("expected a not nullable column, got %v, %v", nullable, ok)
+	}
+	if scanType := types[0].ScanType(); scanType != reflect.TypeOf(int32(0)) {
+		t.Errorf("expected int32, got %v", scanType)
+	}
+
+	if length, ok := types[1].Length(); !ok || length != 20 {
+		t.Errorf("expected length 20, got %d, %v", length, ok)
+	}
+	if nullable, ok := types[1].Nullable(); !ok || !nullable {
+		t.Errorf("expected a nullable column, got %v, %v", nullable, ok)
+	}
+
+	if precision, scale, ok := types[2].DecimalSize(); !ok || precision != 10 || scale != 2 {
+		t.Errorf("expected decimal(10,2), got %d, %d, %v", precision, scale, ok)
+	}
+	if scanType := types[2].ScanType(); scanType != reflect.TypeOf(float64(0)) {
+		t.Errorf("expected float64, got %v", scanType)
+	}
+
+	if length, ok := types[3].Length(); !ok || length != 8 {
+		t.Errorf("expected length 8, got %d, %v", length, ok)
+	}
+
+	if scanType := types[4].ScanType(); scanType != reflect.TypeOf(float32(0)) {
+		t.Errorf("expected float32, got %v", scanType)
+	}
+}
+
+func TestConn_exec_and_query_without_prepare(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( a int, b character varying(20) );")
+
+	ctx := context.Background()
+	conn, err := tdb.db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn any) error {
+		execer, ok := driverConn.(driver.ExecerContext)
+		if !ok {
+			return fmt.Errorf("%T is not a driver.ExecerContext", driverConn)
+		}
+
+		res, err := execer.ExecContext(ctx, "insert into t0 values ( ?, @b );", []driver.NamedValue{
+			{Ordinal: 1, Value: int64(1)},
+			{Name: "b", Ordinal: 2, Value: "one"},
+		})
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n != 1 {
+			return fmt.Errorf("expected 1 row affected, got %d, %v", n, err)
+		}
+
+		queryer, ok := driverConn.(driver.QueryerContext)
+		if !ok {
+			return fmt.Errorf("%T is not a driver.QueryerContext", driverConn)
+		}
+
+		rows, err := queryer.QueryContext(ctx, "select b from t0 where a = ?;", []driver.NamedValue{
+			{Ordinal: 1, Value: int64(1)},
+		})
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		dest := make([]driver.Value, 1)
+		if err := rows.Next(dest); err != nil {
+			return err
+		}
+		if dest[0] != "one" {
+			return fmt.Errorf("expected one, got %v", dest[0])
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStmt_exec_repeated_name(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( a int, b int, c character varying(20) );")
+
+	ctx := context.Background()
+	conn, err := tdb.db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// The legacy driver.Stmt path, binding []driver.Value.
+	err = conn.Raw(func(driverConn any) error {
+		st, err := driverConn.(driver.Conn).Prepare("insert into t0 values ( @n1, @n1, ? );")
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		if n := st.NumInput(); n != 2 {
+			return fmt.Errorf("expected 2 inputs, got %d", n)
+		}
+		_, err = st.Exec([]driver.Value{int64(7), "seven"})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var a, b int
+	var c string
+	if err := tdb.db.QueryRow("select a, b, c from t0;").Scan(&a, &b, &c); err != nil {
+		t.Fatal(err)
+	}
+	if a != 7 || b != 7 || c != "seven" {
+		t.Errorf("expected 7, 7, seven, got %d, %d, %s", a, b, c)
+	}
+}
+
+func TestQuery_multiple_result_sets(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( val int );")
+	tdb.mustExec("insert into t0 values ( 1 );")
+
+	rows, err := tdb.db.Query("select val from t0; select val + 1, val + 2 from t0;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var first int32
+	for rows.Next() {
+		if err := rows.Scan(&first); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !rows.NextResultSet() {
+		t.Fatalf("expected a second result set, %v", rows.Err())
+	}
+
+	var second, third int32
+	for rows.Next() {
+		if err := rows.Scan(&second, &third); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if first != 1 || second != 2 || third != 3 {
+		t.Errorf("expected 1, 2 and 3, got %d, %d and %d", first, second, third)
+	}
+
+	if rows.NextResultSet() {
+		t.Error("expected no third result set")
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQuery_fetch_size(t *testing.T) {
+	tdb := createTempdbWithOptions(t, "fetchsize=3")
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( val int );")
+	for i := 1; i <= 7; i++ {
+		tdb.mustExec("insert into t0 values ( ? );", i)
+	}
+
+	conn, err := tdb.db.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Blocks of at most the fetch size, the last one short.
+	fixture := []struct {
+		name   string
+		ctx    context.Context
+		blocks int
+	}{
+		{"connection fetch size", context.Background(), 3},
+		{"fetch size of the query", WithFetchSize(context.Background(), 2), 4},
+		{"fetch size of most rows", WithFetchSize(context.Background(), 5), 2},
+		{"FBCAccess fetch size", WithFetchSize(context.Background(), 0), 0},
+	}
+
+	for _, tcase := range fixture {
+		err := conn.Raw(func(driverConn any) error {
+			driverRows, err := driverConn.(driver.QueryerContext).QueryContext(tcase.ctx, "select val from t0 order by val;", nil)
+			if err != nil {
+				return err
+			}
+			defer driverRows.Close()
+
+			rows := driverRows.(*Rows)
+			dest := make([]driver.Value, 1)
+
+			var sum, count int32
+			for {
+				if err := rows.Next(dest); err == io.EOF {
+					break
+				} else if err != nil {
+					return err
+				}
+				if rows.block != nil && rows.block.read > rows.fetchSize {
+					return fmt.Errorf("read %d rows from a block of %d", rows.block.read, rows.fetchSize)
+				}
+				sum += dest[0].(int32)
+				count++
+			}
+
+			if count != 7 || sum != 28 {
+				return fmt.Errorf("expected 7 rows summing to 28, got %d rows summing to %d", count, sum)
+			}
+			if rows.blocks != tcase.blocks {
+				return fmt.Errorf("expected %d blocks, got %d", tcase.blocks, rows.blocks)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("case '%s' unexpected error '%v'", tcase.name, err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package frontbase
+
+/*
+#include "clib.h"
+*/
+import "C"
+import (
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+//
+// The result of executing a statement
+//
+
+// A Result is the outcome of a statement: the number of rows it
+// affected and, for an INSERT into a table with a UNIQUE default,
+// the key generated for the inserted row.
+//
+// database/sql hands out only the sql.Result methods. The key as
+// an exact Decimal is reached by executing through the connection:
+//
+//	err := conn.Raw(func(dc any) error {
+//		res, err := dc.(*frontbase.Conn).ExecContext(ctx, query, nil)
+//		...
+//		key, err := res.(frontbase.Result).InsertKey()
+type Result struct {
+	statementType string
+	rowsAffected  int64
+	insertKey     string // empty when no key was generated
+}
+
+func newResult(md *C.FBCMetaData) Result {
+	res := Result{
+		rowsAffected: int64(C.fbcmdRowCount(md)),
+	}
+
+	if tp := C.fbcmdStatementType(md); tp != nil {
+		res.statementType = C.GoString(tp)
+	}
+
+	if res.isInsert() {
+		if key := C.GoFBGeneratedKey(md); key != nil {
+			res.insertKey = C.GoString(key)
+			C.free(unsafe.Pointer(key))
+		}
+	}
+
+	return res
+}
+
+func (res Result) isSelect() bool {
+	return strings.HasPrefix(strings.ToUpper(res.statementType), "SELECT")
+}
+
+func (res Result) isInsert() bool {
+	return strings.HasPrefix(strings.ToUpper(res.statementType), "INSERT")
+}
+
+// InsertKey returns the key generated by the UNIQUE default of the
+// table for the row inserted, as an exact Decimal for keys that
+// LastInsertId can't return as an int64.
+func (res Result) InsertKey() (Decimal, error) {
+	if !res.isInsert() {
+		return Decimal{}, fmt.Errorf("no generated key for %q statements", res.statementType)
+	}
+	if res.rowsAffected != 1 {
+		return Decimal{}, fmt.Errorf("no single generated key for %d rows inserted", res.rowsAffected)
+	}
+	if res.insertKey == "" {
+		return Decimal{}, fmt.Errorf("no key generated, the table has no UNIQUE default")
+	}
+	return ParseDecimal(res.insertKey)
+}
+
+// LastInsertId returns the key generated by the UNIQUE default of
+// the table for the row inserted. It fails when there is no such
+// key or it is not an int64; see InsertKey.
+func (res Result) LastInsertId() (int64, error) {
+	key, err := res.InsertKey()
+	if err != nil {
+		return 0, err
+	}
+
+	unscaled := key.Unscaled()
+	if key.Scale() != 0 || !unscaled.IsInt64() {
+		return 0, fmt.Errorf("generated key %s is not an int64, use Result.InsertKey", key)
+	}
+	return unscaled.Int64(), nil
+}
+
+func (res Result) RowsAffected() (int64, error) {
+	return res.rowsAffected, nil
+}
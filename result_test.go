@@ -0,0 +1,74 @@
+package frontbase
+
+import (
+	"testing"
+)
+
+func TestResult_LastInsertId(t *testing.T) {
+	fixture := []struct {
+		name     string
+		res      Result
+		expected int64
+		failure  string
+	}{
+		{
+			"generated key",
+			Result{statementType: "INSERT", rowsAffected: 1, insertKey: "42"},
+			42,
+			"",
+		},
+		{
+			"no UNIQUE default",
+			Result{statementType: "INSERT", rowsAffected: 1},
+			0,
+			"no key generated, the table has no UNIQUE default",
+		},
+		{
+			"several rows",
+			Result{statementType: "INSERT", rowsAffected: 2, insertKey: "42"},
+			0,
+			"no single generated key for 2 rows inserted",
+		},
+		{
+			"update",
+			Result{statementType: "UPDATE", rowsAffected: 1},
+			0,
+			`no generated key for "UPDATE" statements`,
+		},
+		{
+			"key beyond int64",
+			Result{statementType: "INSERT", rowsAffected: 1, insertKey: "12345678901234567890"},
+			0,
+			"generated key 12345678901234567890 is not an int64, use Result.InsertKey",
+		},
+	}
+
+	for _, tcase := range fixture {
+		actual, err := tcase.res.LastInsertId()
+
+		if tcase.failure != "" {
+			if err == nil || err.Error() != tcase.failure {
+				t.Errorf("case '%s' expected error '%s' but got '%v'", tcase.name, tcase.failure, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("case '%s' unexpected error '%v'", tcase.name, err)
+		} else if actual != tcase.expected {
+			t.Errorf("case '%s' expected %d but got %d", tcase.name, tcase.expected, actual)
+		}
+	}
+}
+
+func TestResult_InsertKey(t *testing.T) {
+	res := Result{statementType: "INSERT", rowsAffected: 1, insertKey: "12345678901234567890"}
+
+	key, err := res.InsertKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.String() != "12345678901234567890" {
+		t.Errorf("expected 12345678901234567890, got %s", key)
+	}
+}
@@ -0,0 +1,103 @@
+package frontbase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+//
+// Retrying transactions
+//
+
+// Default values used by RunInTx for empty RetryOptions fields.
+const (
+	defaultMaxAttempts = 5
+	defaultBackoff     = 10 * time.Millisecond
+	defaultMaxBackoff  = time.Second
+)
+
+// RetryOptions controls how RunInTx retries a transaction.
+type RetryOptions struct {
+	TxOptions   *sql.TxOptions               // passed to db.BeginTx
+	MaxAttempts int                          // attempts in total, defaults to 5
+	Backoff     time.Duration                // wait before the first retry, doubled after each, defaults to 10ms
+	MaxBackoff  time.Duration                // upper bound of the wait, defaults to 1s
+	OnRetry     func(attempt int, err error) // called before each retry, if set
+}
+
+// RunInTx runs fn in a transaction and commits it. When fn, or the
+// commit, fails with a lock conflict or a serialization failure the
+// transaction is rolled back and fn is run again in a new one,
+// after a backoff. Any other error rolls back and is returned as is.
+//
+// opts may be nil. fn may be called several times and must not
+// have side effects outside the transaction.
+func RunInTx(ctx context.Context, db *sql.DB, opts *RetryOptions, fn func(*sql.Tx) error) error {
+	if opts == nil {
+		opts = &RetryOptions{}
+	}
+
+	return retry(ctx, *opts, func() error {
+		tx, err := db.BeginTx(ctx, opts.TxOptions)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(tx); err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return errors.Join(err, rbErr)
+			}
+			return err
+		}
+
+		return tx.Commit()
+	})
+}
+
+// Reports whether a failed transaction is worth another attempt.
+func isRetryable(err error) bool {
+	return IsSerializationFailure(err) || IsLockConflict(err)
+}
+
+// Call attempt until it succeeds, fails with an error that is not
+// retryable, the attempts run out or ctx is done.
+func retry(ctx context.Context, opts RetryOptions, attempt func() error) error {
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	backoff := opts.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+	maxBackoff := opts.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	for n := 1; ; n++ {
+		err := attempt()
+		if err == nil || n >= maxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		if opts.OnRetry != nil {
+			opts.OnRetry(n, err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
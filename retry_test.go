@@ -0,0 +1,84 @@
+package frontbase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	conflict := &Error{Code: CodeSerializationFailure}
+	other := errors.New("boom")
+
+	fixture := []struct {
+		name          string
+		failures      []error
+		maxAttempts   int
+		expected      error
+		expectedCalls int
+	}{
+		{"success", nil, 3, nil, 1},
+		{"conflict then success", []error{conflict}, 3, nil, 2},
+		{"conflicts exhaust attempts", []error{conflict, conflict, conflict}, 3, conflict, 3},
+		{"other error is not retried", []error{other}, 3, other, 1},
+		{"conflict then other error", []error{conflict, other}, 3, other, 2},
+	}
+
+	for _, tcase := range fixture {
+		calls := 0
+		retries := 0
+
+		opts := RetryOptions{
+			MaxAttempts: tcase.maxAttempts,
+			Backoff:     time.Microsecond,
+			OnRetry:     func(int, error) { retries++ },
+		}
+
+		err := retry(context.Background(), opts, func() error {
+			calls++
+			if calls <= len(tcase.failures) {
+				return tcase.failures[calls-1]
+			}
+			return nil
+		})
+
+		if err != tcase.expected {
+			t.Errorf("case '%s' expected error %v but got %v", tcase.name, tcase.expected, err)
+		}
+		if calls != tcase.expectedCalls {
+			t.Errorf("case '%s' expected %d calls but got %d", tcase.name, tcase.expectedCalls, calls)
+		}
+		if retries != calls-1 {
+			t.Errorf("case '%s' expected %d retries reported but got %d", tcase.name, calls-1, retries)
+		}
+	}
+}
+
+func TestRetry_context_done(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	opts := RetryOptions{Backoff: time.Hour}
+	err := retry(ctx, opts, func() error {
+		return ErrorList{{Code: CodeLockConflict}}
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestConn_failed_commit_ends_transaction(t *testing.T) {
+	for _, end := range []func(*Conn) error{(*Conn).commit, (*Conn).rollback} {
+		// A bad connection fails every statement.
+		dc := &Conn{inTx: true, nestLevel: 2, bad: true}
+
+		if err := end(dc); err == nil {
+			t.Error("expected an error")
+		}
+		if dc.inTx || dc.nestLevel != 0 {
+			t.Errorf("expected the transaction to be over, got inTx %v and nestLevel %d", dc.inTx, dc.nestLevel)
+		}
+	}
+}
@@ -5,19 +5,51 @@ package frontbase
 */
 import "C"
 import (
+	"context"
 	"database/sql/driver"
 	"fmt"
 	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
 	"time"
 	"unsafe"
 )
 
 type Rows struct {
-	md *C.FBCMetaData
+	md  *C.FBCMetaData // of the current result set
+	dc  *Conn
+	ctx context.Context // of the query, for fetching more rows
+
+	// When the statement returned several result sets, md is one
+	// of the list and index its position there.
+	list  *C.FBCMetaData
+	index int
+
+	// With a fetch size the rows are read from blocks of at most
+	// that many rows, one fetched from the server at a time.
+	fetchSize int
+	block     *rowBlock
+	blocks    int // read from the current result set so far
+}
+
+func newRows(ctx context.Context, md *C.FBCMetaData, dc *Conn) *Rows {
+	rows := &Rows{md: md, dc: dc, ctx: ctx, fetchSize: fetchSizeFor(ctx, dc)}
+
+	if C.fbcmdHasMetaDataArray(md) != 0 {
+		rows.list = md
+		rows.md = C.fbcmdMetaDataAtIndex(md, 0)
+	}
+
+	return rows
 }
 
 func (rows *Rows) Next(dest []driver.Value) error {
-	row := C.fbcmdFetchRow(rows.md)
+	row, err := rows.fetchRow()
+	if err != nil {
+		return err
+	}
 	if row == nil {
 		return io.EOF
 	}
@@ -52,14 +84,28 @@ func (rows *Rows) Next(dest []driver.Value) error {
 		case C.FB_LongInteger:
 			dest[i] = C.GoFBColumnValueLongInt(col)
 		case C.FB_TimestampTZ:
-			fallthrough
+			t := timestampFromSeconds(float64(C.GoFBColumnValueSeconds(col)), fractionDigits(dataType))
+			dest[i] = t.In(columnZone(col))
 		case C.FB_Timestamp:
-			tval := C.struct_GoFBTimestampValue{
-				secs:  0,
-				nsecs: 0,
-			}
-			C.GoFBColumnValueTimestamp(col, &tval)
-			dest[i] = time.Unix(int64(tval.secs), int64(tval.nsecs))
+			t := timestampFromSeconds(float64(C.GoFBColumnValueSeconds(col)), fractionDigits(dataType))
+			dest[i] = t.In(rows.dc.location)
+		case C.FB_Date:
+			// Seconds from 2001-01-01 to midnight of the date.
+			secs := int64(math.Floor(float64(C.GoFBColumnValueSeconds(col))))
+			y, m, d := time.Unix(secs+frontbaseEpoch, 0).UTC().Date()
+			dest[i] = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		case C.FB_TimeTZ:
+			d := durationFromSeconds(float64(C.GoFBColumnValueSeconds(col)), fractionDigits(dataType))
+			dest[i] = time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Add(d).In(columnZone(col))
+		case C.FB_Time:
+			// Seconds since midnight, on the zero date like other drivers.
+			d := durationFromSeconds(float64(C.GoFBColumnValueSeconds(col)), fractionDigits(dataType))
+			dest[i] = time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC).Add(d)
+		case C.FB_YearMonth:
+			// As text, which standard destinations and Interval scan.
+			dest[i] = Interval{yearMonth: true, months: int64(C.GoFBColumnValueYearMonth(col))}.String()
+		case C.FB_DayTime:
+			dest[i] = DayTimeInterval(durationFromSeconds(float64(C.GoFBColumnValueSeconds(col)), 9)).String()
 		case C.FB_Character:
 			fallthrough
 		case C.FB_VCharacter:
@@ -68,12 +114,25 @@ func (rows *Rows) Next(dest []driver.Value) error {
 			fallthrough
 		case C.FB_VBit:
 			dest[i] = C.GoBytes(unsafe.Pointer(C.GoFBColumnValueBit(col)), C.GoFBColumnSizeBit(col))
-		case C.FB_Float:
+		case C.FB_Float, C.FB_Real:
 			dest[i] = float32(C.GoFBColumnValueDouble(col))
 		case C.FB_Double:
 			dest[i] = float64(C.GoFBColumnValueDouble(col))
-		case C.FB_Decimal:
-			dest[i] = float64(C.GoFBColumnValueDecimal(col))
+		case C.FB_BLOB, C.FB_CLOB:
+			v, err := rows.dc.readLOB(col, dtc == C.FB_CLOB)
+			if err != nil {
+				return err
+			}
+			dest[i] = v
+		case C.FB_Decimal, C.FB_Numeric:
+			if rows.dc.exactDecimals {
+				// The server hands out a double; formatted with the
+				// column's scale it is exact up to 15 significant digits.
+				scale := int(C.fbcdmdScale(dataType))
+				dest[i] = strconv.FormatFloat(float64(C.GoFBColumnValueDecimal(col)), 'f', scale, 64)
+			} else {
+				dest[i] = float64(C.GoFBColumnValueDecimal(col))
+			}
 		default:
 			return fmt.Errorf("unsupported dtc %v", dtc)
 		}
@@ -82,6 +141,79 @@ func (rows *Rows) Next(dest []driver.Value) error {
 	return nil
 }
 
+// Seconds between the Unix epoch and the FrontBase epoch,
+// 2001-01-01 00:00:00 UTC.
+const frontbaseEpoch = 978307200
+
+// Convert a timestamp as seconds since the FrontBase epoch.
+func timestampFromSeconds(secs float64, digits int) time.Time {
+	whole, nsecs := splitSeconds(secs, digits)
+	return time.Unix(whole+frontbaseEpoch, nsecs)
+}
+
+// Convert a time of day or an interval as seconds.
+func durationFromSeconds(secs float64, digits int) time.Duration {
+	whole, nsecs := splitSeconds(secs, digits)
+	return time.Duration(whole)*time.Second + time.Duration(nsecs)
+}
+
+// Split `secs` into whole seconds and nanoseconds, rounded to
+// `digits` decimals, the precision of the column, since the double
+// carries noise below that. The double is taken apart exactly, so
+// that rounding is the only one; where the double can't resolve
+// `digits` decimals, e.g. microseconds a thousand years from 2001,
+// it is rounded to the decimals it can.
+func splitSeconds(secs float64, digits int) (whole, nsecs int64) {
+	if math.IsNaN(secs) || math.IsInf(secs, 0) {
+		return 0, 0
+	}
+
+	// The difference to the next double is the resolution; a
+	// decimal finer than that is noise.
+	ulp := math.Nextafter(math.Abs(secs), math.Inf(1)) - math.Abs(secs)
+	for digits > 0 && math.Pow10(-digits) <= ulp {
+		digits--
+	}
+
+	// secs * 10^digits rounded half up, in units of 10^-digits.
+	exact := new(big.Rat).SetFloat64(secs)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+
+	num := new(big.Int).Mul(exact.Num(), scale)
+	num.Lsh(num, 1)
+	num.Add(num, exact.Denom())
+	den := new(big.Int).Lsh(exact.Denom(), 1)
+	units := new(big.Int).Div(num, den) // Euclidean, so floored
+
+	w, frac := new(big.Int).DivMod(units, scale, new(big.Int))
+
+	nsecs = frac.Int64()
+	for i := digits; i < 9; i++ {
+		nsecs *= 10
+	}
+	return w.Int64(), nsecs
+}
+
+// The fractional seconds precision of a temporal column. Columns
+// that report none are decoded with microseconds, which a double
+// holds exactly for the dates in common use.
+func fractionDigits(dataType *C.FBCDatatypeMetaData) int {
+	digits := int(C.fbcdmdScale(dataType))
+	if digits <= 0 || digits > 9 {
+		return 6
+	}
+	return digits
+}
+
+// The zone of a value WITH TIME ZONE, as stored.
+func columnZone(col *C.FBCColumn) *time.Location {
+	offset := int(C.GoFBColumnValueTimeZoneOffset(col))
+	if offset == 0 {
+		return time.UTC
+	}
+	return time.FixedZone("", offset)
+}
+
 func (rows *Rows) Columns() []string {
 	numCols := C.fbcmdColumnCount(rows.md)
 	cols := make([]string, numCols)
@@ -95,11 +227,181 @@ func (rows *Rows) Columns() []string {
 }
 
 func (rows *Rows) Close() error {
+	rows.block.release()
+	rows.block = nil
+
 	if rows.md != nil {
-		C.fbcmdRelease(rows.md)
+		if rows.list != nil {
+			// The result sets are released with their list.
+			C.fbcmdRelease(rows.list)
+			rows.list = nil
+		} else {
+			C.fbcmdRelease(rows.md)
+		}
 		rows.md = nil
 		return nil
 	} else {
 		return fmt.Errorf("Rows iterator already closed")
 	}
 }
+
+// RowsNextResultSet
+func (rows *Rows) HasNextResultSet() bool {
+	return rows.list != nil && rows.index+1 < int(C.fbcmdCount(rows.list))
+}
+
+// RowsNextResultSet
+func (rows *Rows) NextResultSet() error {
+	if !rows.HasNextResultSet() {
+		return io.EOF
+	}
+
+	rows.block.release()
+	rows.block = nil
+	rows.blocks = 0
+
+	rows.index++
+	rows.md = C.fbcmdMetaDataAtIndex(rows.list, C.uint(rows.index))
+	return nil
+}
+
+//
+// Column type metadata
+//
+
+func (rows *Rows) datatype(index int) *C.FBCDatatypeMetaData {
+	cmd := C.fbcmdColumnMetaDataAtIndex(rows.md, C.uint(index))
+	return C.fbccmdDatatype(cmd)
+}
+
+func (rows *Rows) datatypeCode(index int) C.int {
+	return C.int(C.fbcdmdDatatypeCode(rows.datatype(index)))
+}
+
+// RowsColumnTypeDatabaseTypeName
+func (rows *Rows) ColumnTypeDatabaseTypeName(index int) string {
+	switch rows.datatypeCode(index) {
+	case C.FB_Boolean:
+		return "BOOLEAN"
+	case C.FB_TinyInteger:
+		return "TINYINT"
+	case C.FB_SmallInteger:
+		return "SMALLINT"
+	case C.FB_Integer:
+		return "INTEGER"
+	case C.FB_LongInteger:
+		return "LONGINT"
+	case C.FB_Timestamp:
+		return "TIMESTAMP"
+	case C.FB_TimestampTZ:
+		return "TIMESTAMP WITH TIME ZONE"
+	case C.FB_Character:
+		return "CHARACTER"
+	case C.FB_VCharacter:
+		return "CHARACTER VARYING"
+	case C.FB_Bit:
+		return "BIT"
+	case C.FB_VBit:
+		return "BIT VARYING"
+	case C.FB_Float:
+		return "FLOAT"
+	case C.FB_Real:
+		return "REAL"
+	case C.FB_Double:
+		return "DOUBLE PRECISION"
+	case C.FB_Numeric:
+		return "NUMERIC"
+	case C.FB_Decimal:
+		return "DECIMAL"
+	case C.FB_Date:
+		return "DATE"
+	case C.FB_Time:
+		return "TIME"
+	case C.FB_TimeTZ:
+		return "TIME WITH TIME ZONE"
+	case C.FB_YearMonth:
+		return "INTERVAL YEAR TO MONTH"
+	case C.FB_DayTime:
+		return "INTERVAL DAY TO SECOND"
+	case C.FB_BLOB:
+		return "BLOB"
+	case C.FB_CLOB:
+		return "CLOB"
+	default:
+		return ""
+	}
+}
+
+// RowsColumnTypeScanType
+func (rows *Rows) ColumnTypeScanType(index int) reflect.Type {
+	switch rows.datatypeCode(index) {
+	case C.FB_Boolean:
+		return reflect.TypeOf(false)
+	case C.FB_TinyInteger:
+		return reflect.TypeOf(int8(0))
+	case C.FB_SmallInteger:
+		return reflect.TypeOf(int16(0))
+	case C.FB_Integer:
+		return reflect.TypeOf(int32(0))
+	case C.FB_LongInteger:
+		return reflect.TypeOf(int64(0))
+	case C.FB_Timestamp, C.FB_TimestampTZ, C.FB_Date, C.FB_Time, C.FB_TimeTZ:
+		return reflect.TypeOf(time.Time{})
+	case C.FB_Character, C.FB_VCharacter:
+		return reflect.TypeOf("")
+	case C.FB_Bit, C.FB_VBit:
+		return reflect.TypeOf([]byte(nil))
+	case C.FB_YearMonth, C.FB_DayTime:
+		// The text, for scanning into an Interval.
+		return reflect.TypeOf("")
+	case C.FB_BLOB, C.FB_CLOB:
+		if rows.dc.blobLOBs {
+			return reflect.TypeOf((*Blob)(nil))
+		}
+		if rows.datatypeCode(index) == C.FB_CLOB {
+			return reflect.TypeOf("")
+		}
+		return reflect.TypeOf([]byte(nil))
+	case C.FB_Float, C.FB_Real:
+		return reflect.TypeOf(float32(0))
+	case C.FB_Double:
+		return reflect.TypeOf(float64(0))
+	case C.FB_Decimal, C.FB_Numeric:
+		if rows.dc.exactDecimals {
+			// The digits, for scanning into a Decimal.
+			return reflect.TypeOf("")
+		}
+		return reflect.TypeOf(float64(0))
+	default:
+		return reflect.TypeOf((*any)(nil)).Elem()
+	}
+}
+
+// RowsColumnTypeNullable
+func (rows *Rows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	cmd := C.fbcmdColumnMetaDataAtIndex(rows.md, C.uint(index))
+	return C.fbccmdIsNullable(cmd) != 0, true
+}
+
+// RowsColumnTypeLength
+func (rows *Rows) ColumnTypeLength(index int) (length int64, ok bool) {
+	switch rows.datatypeCode(index) {
+	case C.FB_Character, C.FB_VCharacter, C.FB_Bit, C.FB_VBit:
+		return int64(C.fbcdmdLength(rows.datatype(index))), true
+	case C.FB_BLOB, C.FB_CLOB:
+		return math.MaxInt64, true
+	default:
+		return 0, false
+	}
+}
+
+// RowsColumnTypePrecisionScale
+func (rows *Rows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	switch rows.datatypeCode(index) {
+	case C.FB_Decimal, C.FB_Numeric:
+		dt := rows.datatype(index)
+		return int64(C.fbcdmdPrecision(dt)), int64(C.fbcdmdScale(dt)), true
+	default:
+		return 0, 0, false
+	}
+}
@@ -0,0 +1,177 @@
+package frontbase
+
+/*
+#include "clib.h"
+*/
+import "C"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//
+// Savepoints
+//
+
+// Savepoint establishes a savepoint in the connection's current
+// transaction. It is reached through sql.Conn.Raw:
+//
+//	err := conn.Raw(func(dc any) error {
+//		return dc.(*frontbase.Conn).Savepoint(ctx, "before_import")
+//	})
+func (dc *Conn) Savepoint(ctx context.Context, name string) error {
+	return dc.savepointExec(ctx, "savepoint %s;", name)
+}
+
+// RollbackTo rolls the connection's current transaction back to the
+// savepoint `name`.
+func (dc *Conn) RollbackTo(ctx context.Context, name string) error {
+	return dc.savepointExec(ctx, "rollback to savepoint %s;", name)
+}
+
+// Release destroys the savepoint `name`, keeping its changes.
+func (dc *Conn) Release(ctx context.Context, name string) error {
+	return dc.savepointExec(ctx, "release savepoint %s;", name)
+}
+
+func (dc *Conn) savepointExec(ctx context.Context, format string, name string) error {
+	if !dc.inTx {
+		return fmt.Errorf("savepoint %s: no transaction in progress", name)
+	}
+
+	query := fmt.Sprintf(format, quoteIdentifier(name))
+	md, err := dc.exec(ctx, query, false, false)
+	if err != nil {
+		return err
+	}
+	C.fbcmdRelease(md)
+	return nil
+}
+
+//
+// Nested transactions
+//
+
+// InNestedTx runs fn in a transaction on conn. If conn has no
+// transaction in progress one is started with conn.BeginTx and then
+// committed when fn succeeds, or rolled back when it fails. If conn
+// already is in a transaction a savepoint is established instead and
+// fn's changes are released into, or rolled back out of, the outer
+// transaction.
+//
+// This lets transactional functions call each other freely as long
+// as they all run their statements on conn. A panic in fn rolls back
+// like an error before it propagates; a failed rollback is reported
+// along with fn's error.
+func InNestedTx(ctx context.Context, conn *sql.Conn, fn func(ctx context.Context) error) error {
+	var inTx bool
+	err := conn.Raw(func(driverConn any) error {
+		dc, err := frontbaseConn(driverConn, "InNestedTx")
+		if err != nil {
+			return err
+		}
+		inTx = dc.inTx
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !inTx {
+		return inOuterTx(ctx, conn, fn)
+	}
+	return inSavepoint(ctx, conn, fn)
+}
+
+func inOuterTx(ctx context.Context, conn *sql.Conn, fn func(ctx context.Context) error) (err error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = errors.Join(err, rbErr)
+			}
+		}
+	}()
+
+	if err := fn(ctx); err != nil {
+		return err
+	}
+
+	succeeded = true
+	return tx.Commit()
+}
+
+func inSavepoint(ctx context.Context, conn *sql.Conn, fn func(ctx context.Context) error) (err error) {
+	var savepoint string
+
+	err = conn.Raw(func(driverConn any) error {
+		dc, err := frontbaseConn(driverConn, "InNestedTx")
+		if err != nil {
+			return err
+		}
+
+		dc.nestLevel++
+		savepoint = fmt.Sprintf("go_frontbase_%d", dc.nestLevel)
+		if err := dc.Savepoint(ctx, savepoint); err != nil {
+			dc.nestLevel--
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	succeeded := false
+	defer func() {
+		endErr := conn.Raw(func(driverConn any) error {
+			dc, err := frontbaseConn(driverConn, "InNestedTx")
+			if err != nil {
+				return err
+			}
+
+			if dc.nestLevel > 0 {
+				dc.nestLevel--
+			}
+			if !succeeded {
+				if err := dc.RollbackTo(ctx, savepoint); err != nil {
+					return err
+				}
+			}
+			return dc.Release(ctx, savepoint)
+		})
+		if endErr != nil {
+			err = errors.Join(err, endErr)
+		}
+	}()
+
+	if err := fn(ctx); err != nil {
+		return err
+	}
+
+	succeeded = true
+	return nil
+}
+
+// The FrontBase connection `driverConn` handed out by sql.Conn.Raw,
+// or an error naming `caller` when it is another driver's.
+func frontbaseConn(driverConn any, caller string) (*Conn, error) {
+	dc, ok := driverConn.(*Conn)
+	if !ok {
+		return nil, fmt.Errorf("%s: %T is not a FrontBase connection", caller, driverConn)
+	}
+	return dc, nil
+}
+
+// Quote `name` as an SQL delimited identifier.
+func quoteIdentifier(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
@@ -0,0 +1,104 @@
+package frontbase
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestInNestedTx(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( val int );")
+
+	ctx := context.Background()
+	conn, err := tdb.db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	innerErr := errors.New("inner failure")
+
+	err = InNestedTx(ctx, conn, func(ctx context.Context) error {
+		if _, err := conn.ExecContext(ctx, "insert into t0 values ( 1 );"); err != nil {
+			return err
+		}
+
+		err := InNestedTx(ctx, conn, func(ctx context.Context) error {
+			if _, err := conn.ExecContext(ctx, "insert into t0 values ( 2 );"); err != nil {
+				return err
+			}
+			return innerErr
+		})
+		if err != innerErr {
+			t.Errorf("expected %v, got %v", innerErr, err)
+		}
+
+		return InNestedTx(ctx, conn, func(ctx context.Context) error {
+			_, err := conn.ExecContext(ctx, "insert into t0 values ( 3 );")
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sum int32
+	if err := tdb.db.QueryRow("select sum(val) from t0;").Scan(&sum); err != nil {
+		t.Fatal(err)
+	}
+	if sum != 4 {
+		t.Errorf("expected rows 1 and 3 to be committed, got sum %d", sum)
+	}
+}
+
+func TestInNestedTx_panic(t *testing.T) {
+	tdb := createTempdb(t)
+	defer tdb.tearDown()
+
+	tdb.mustExec("create table t0 ( val int );")
+
+	ctx := context.Background()
+	conn, err := tdb.db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	err = InNestedTx(ctx, conn, func(ctx context.Context) error {
+		if _, err := conn.ExecContext(ctx, "insert into t0 values ( 1 );"); err != nil {
+			return err
+		}
+
+		func() {
+			defer func() { recover() }()
+
+			InNestedTx(ctx, conn, func(ctx context.Context) error {
+				if _, err := conn.ExecContext(ctx, "insert into t0 values ( 2 );"); err != nil {
+					return err
+				}
+				panic("inner panic")
+			})
+		}()
+
+		return conn.Raw(func(driverConn any) error {
+			if level := driverConn.(*Conn).nestLevel; level != 0 {
+				t.Errorf("expected the nesting level restored to 0, got %d", level)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sum int32
+	if err := tdb.db.QueryRow("select sum(val) from t0;").Scan(&sum); err != nil {
+		t.Fatal(err)
+	}
+	if sum != 1 {
+		t.Errorf("expected only row 1 to be committed, got sum %d", sum)
+	}
+}
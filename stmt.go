@@ -11,6 +11,11 @@ import (
 	"github.com/Oops-AB/go-frontbase/prepared"
 )
 
+// A statement prepared on the client. FBCAccess has no API for
+// preparing a statement on the server and sending the parameters
+// separately, so every execution binds the arguments as literals
+// into the SQL text, see prepared.Stmt, and ships that through
+// fbcdcExecuteSQL.
 type stmt struct {
 	dc     *Conn
 	pstmt  *prepared.Stmt
@@ -29,31 +34,22 @@ func (st *stmt) Query(args []driver.Value) (driver.Rows, error) {
 	}
 
 	// Execute the SQL query and return a driver.Rows iterator.
-	md, err := st.dc.exec(sql, !st.dc.inTx, false)
+	md, err := st.dc.exec(context.Background(), sql, !st.dc.inTx, false)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Rows{
-		md: md,
-	}, nil
+	return newRows(context.Background(), md, st.dc), nil
 }
 
 func (st *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
-	sql, err := st.pstmt.BindNamed(args)
-	if err != nil {
-		return nil, err
-	}
-
 	// Execute the SQL query and return a driver.Rows iterator.
-	md, err := st.dc.exec(sql, !st.dc.inTx, false)
+	md, err := st.dc.execBound(ctx, st.pstmt, args)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Rows{
-		md: md,
-	}, nil
+	return newRows(ctx, md, st.dc), nil
 }
 
 func (st *stmt) Exec(args []driver.Value) (driver.Result, error) {
@@ -63,31 +59,26 @@ func (st *stmt) Exec(args []driver.Value) (driver.Result, error) {
 	}
 
 	// Execute the SQL query and return a driver.Result.
-	md, err := st.dc.exec(sql, !st.dc.inTx, false)
+	md, err := st.dc.exec(context.Background(), sql, !st.dc.inTx, false)
 	if err != nil {
 		return nil, err
 	}
 	defer C.fbcmdRelease(md)
 
-	return driver.ResultNoRows, nil
+	return newResult(md), nil
 }
 
 func (st *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
-	sql, err := st.pstmt.BindNamed(args)
-	if err != nil {
-		return nil, err
-	}
-
 	// Execute the SQL query and return a driver.Result.
-	md, err := st.dc.exec(sql, !st.dc.inTx, false)
+	md, err := st.dc.execBound(ctx, st.pstmt, args)
 	if err != nil {
 		return nil, err
 	}
 	defer C.fbcmdRelease(md)
 
-	return driver.ResultNoRows, nil
+	return newResult(md), nil
 }
 
 func (st *stmt) NumInput() int {
-	return -1
+	return st.pstmt.NumInput()
 }
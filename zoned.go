@@ -0,0 +1,57 @@
+package frontbase
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/Oops-AB/go-frontbase/prepared"
+)
+
+//
+// Times bound with their zone
+//
+
+// A ZonedTime is a time.Time that binds as a TIMESTAMP WITH TIME
+// ZONE literal carrying its offset, rather than as a TIMESTAMP in
+// UTC like a plain time.Time. Use it for values of zoned columns,
+// which then keep the offset:
+//
+//	db.Exec("insert into t values ( ? );", frontbase.ZonedTime{t})
+type ZonedTime struct {
+	time.Time
+}
+
+// prepared.Literal
+func (zt ZonedTime) SQLLiteral() string {
+	return fmt.Sprintf("TIMESTAMP '%s%s'", prepared.FormatTimestamp(zt.Time), zt.Format("-07:00"))
+}
+
+// driver.Valuer
+func (zt ZonedTime) Value() (driver.Value, error) {
+	return zt.Time, nil
+}
+
+// sql.Scanner
+func (zt *ZonedTime) Scan(src any) error {
+	switch v := src.(type) {
+	case time.Time:
+		zt.Time = v
+		return nil
+	case nil:
+		return fmt.Errorf("can't scan NULL into ZonedTime, use sql.Null[ZonedTime]")
+	default:
+		return fmt.Errorf("can't scan %T into ZonedTime", src)
+	}
+}
+
+// A time.Time in a session time zone other than UTC; binds as a
+// TIMESTAMP in that zone.
+type sessionTime struct {
+	time.Time
+}
+
+// prepared.Literal
+func (st sessionTime) SQLLiteral() string {
+	return fmt.Sprintf("TIMESTAMP '%s'", prepared.FormatTimestamp(st.Time))
+}
@@ -0,0 +1,23 @@
+package frontbase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestZonedTime_SQLLiteral(t *testing.T) {
+	fixture := []struct {
+		time     time.Time
+		expected string
+	}{
+		{time.Date(2024, 3, 16, 1, 2, 3, 0, time.UTC), "TIMESTAMP '2024-03-16 01:02:03.000+00:00'"},
+		{time.Date(2024, 3, 16, 1, 2, 3, 4000000, time.FixedZone("", 2*60*60)), "TIMESTAMP '2024-03-16 01:02:03.004+02:00'"},
+		{time.Date(2024, 3, 16, 1, 2, 3, 0, time.FixedZone("", -(5*60*60+30*60))), "TIMESTAMP '2024-03-16 01:02:03.000-05:30'"},
+	}
+
+	for _, tcase := range fixture {
+		if actual := (ZonedTime{tcase.time}).SQLLiteral(); actual != tcase.expected {
+			t.Errorf("expected %s but got %s", tcase.expected, actual)
+		}
+	}
+}